@@ -0,0 +1,240 @@
+package slogging
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+)
+
+// what AsyncHandler does when its queue is full
+type OverflowPolicy int
+
+const (
+	// wait for room in the queue
+	OverflowBlock OverflowPolicy = iota
+	// drop the oldest queued record to make room
+	OverflowDropOldest
+	// drop the new record
+	OverflowDropNew
+)
+
+// returned by AsyncHandler.Handle after Close
+var ErrHandlerClosed = errors.New("handler closed")
+
+// AsyncHandler is a slog.Handler queueing records in a bounded queue,
+// which are passed to the next handler by a background goroutine.
+// Handle returns as soon as the record is queued (or dropped).
+// Errors from the next handler are discarded.
+// Call Close to drain the queue and stop the goroutine.
+// It is registered for the package Flush until closed.
+type AsyncHandler struct {
+	next slog.Handler
+	q    *asyncQueue
+}
+
+// shared by an AsyncHandler and those derived from it with WithAttrs and WithGroup
+type asyncQueue struct {
+	ch     chan asyncItem
+	policy OverflowPolicy
+	done   chan struct{}
+
+	// guards sending on ch in Handle against closing it
+	sendMu sync.RWMutex
+	closed bool
+	// closed first by Close, releasing Handle blocked on a full queue
+	closing   chan struct{}
+	closeOnce sync.Once
+
+	mu      sync.Mutex
+	pending int
+	// closed when pending is 0
+	idle chan struct{}
+
+	dropped atomic.Uint64
+}
+
+type asyncItem struct {
+	ctx context.Context
+	h   slog.Handler
+	r   slog.Record
+}
+
+// create AsyncHandler with a queue of size records, passing records to next
+func NewAsyncHandler(next slog.Handler, size int, policy OverflowPolicy) *AsyncHandler {
+	idle := make(chan struct{})
+	close(idle)
+	q := &asyncQueue{
+		ch:      make(chan asyncItem, max(size, 1)),
+		policy:  policy,
+		done:    make(chan struct{}),
+		closing: make(chan struct{}),
+		idle:    idle}
+	go q.drain()
+	RegisterFlusher(q)
+	return &AsyncHandler{next: next, q: q}
+}
+
+func (h *AsyncHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+// queue a clone of r. Returns ErrHandlerClosed after Close
+func (h *AsyncHandler) Handle(ctx context.Context, r slog.Record) error {
+	return h.q.enqueue(asyncItem{
+		// the caller may cancel ctx as soon as Handle returns
+		ctx: context.WithoutCancel(ctx),
+		h:   h.next,
+		r:   r.Clone()})
+}
+
+func (h *AsyncHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &AsyncHandler{next: h.next.WithAttrs(attrs), q: h.q}
+}
+
+func (h *AsyncHandler) WithGroup(name string) slog.Handler {
+	return &AsyncHandler{next: h.next.WithGroup(name), q: h.q}
+}
+
+// number of records dropped because the queue was full
+func (h *AsyncHandler) Dropped() uint64 {
+	return h.q.dropped.Load()
+}
+
+// wait until all queued records have been handled
+func (h *AsyncHandler) Flush() error {
+	return h.q.Flush()
+}
+
+// wait until all queued records have been handled or ctx is done.
+// Records queued while waiting are accepted and also waited for.
+// If ctx is done first, the returned error wraps ctx.Err() and
+// tells how many records were not yet handled
+func (h *AsyncHandler) FlushContext(ctx context.Context) error {
+	return h.q.FlushContext(ctx)
+}
+
+// stop accepting records, drain the queue and stop the background goroutine.
+// Applies to all handlers derived from h
+func (h *AsyncHandler) Close() error {
+	return h.CloseContext(context.Background())
+}
+
+// as Close, but stop waiting for the queue to drain when ctx is done.
+// The error is then as for FlushContext. New records are rejected with
+// ErrHandlerClosed in any case, also those waiting for room in a full queue
+// (with OverflowBlock), while records still queued are handled by the
+// background goroutine if the next handler becomes unstuck
+func (h *AsyncHandler) CloseContext(ctx context.Context) error {
+	return h.q.CloseContext(ctx)
+}
+
+func (q *asyncQueue) Close() error {
+	return q.CloseContext(context.Background())
+}
+
+func (q *asyncQueue) CloseContext(ctx context.Context) error {
+	DeregisterFlusher(q)
+	// release Handle waiting for room, which holds sendMu
+	q.closeOnce.Do(func() { close(q.closing) })
+	q.sendMu.Lock()
+	if !q.closed {
+		q.closed = true
+		close(q.ch)
+	}
+	q.sendMu.Unlock()
+	select {
+	case <-q.done:
+		return nil
+	case <-ctx.Done():
+		return q.notFlushed(ctx)
+	}
+}
+
+func (q *asyncQueue) Flush() error {
+	return q.FlushContext(context.Background())
+}
+
+func (q *asyncQueue) FlushContext(ctx context.Context) error {
+	q.mu.Lock()
+	idle := q.idle
+	q.mu.Unlock()
+	select {
+	case <-idle:
+		return nil
+	case <-ctx.Done():
+		return q.notFlushed(ctx)
+	}
+}
+
+func (q *asyncQueue) notFlushed(ctx context.Context) error {
+	q.mu.Lock()
+	n := q.pending
+	q.mu.Unlock()
+	return fmt.Errorf("%d records not flushed: %w", n, ctx.Err())
+}
+
+func (q *asyncQueue) enqueue(item asyncItem) error {
+	q.sendMu.RLock()
+	defer q.sendMu.RUnlock()
+	if q.closed {
+		return ErrHandlerClosed
+	}
+
+	q.addPending(1)
+	switch q.policy {
+	case OverflowDropNew:
+		select {
+		case q.ch <- item:
+		default:
+			q.dropped.Add(1)
+			q.addPending(-1)
+		}
+	case OverflowDropOldest:
+		for {
+			select {
+			case q.ch <- item:
+				return nil
+			default:
+			}
+			select {
+			case <-q.ch:
+				q.dropped.Add(1)
+				q.addPending(-1)
+			default:
+			}
+		}
+	default:
+		select {
+		case q.ch <- item:
+		case <-q.closing:
+			q.addPending(-1)
+			return ErrHandlerClosed
+		}
+	}
+	return nil
+}
+
+func (q *asyncQueue) drain() {
+	defer close(q.done)
+	for item := range q.ch {
+		_ = item.h.Handle(item.ctx, item.r)
+		q.addPending(-1)
+	}
+}
+
+func (q *asyncQueue) addPending(n int) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	before := q.pending
+	q.pending += n
+	switch {
+	case before == 0 && q.pending > 0:
+		q.idle = make(chan struct{})
+	case before > 0 && q.pending == 0:
+		close(q.idle)
+	}
+}
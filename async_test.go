@@ -0,0 +1,87 @@
+package slogging
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"testing"
+	"time"
+)
+
+// handler blocking in Handle until release is closed
+type stuckHandler struct {
+	release chan struct{}
+}
+
+func (h stuckHandler) Enabled(context.Context, slog.Level) bool { return true }
+
+func (h stuckHandler) Handle(context.Context, slog.Record) error {
+	<-h.release
+	return nil
+}
+
+func (h stuckHandler) WithAttrs([]slog.Attr) slog.Handler { return h }
+func (h stuckHandler) WithGroup(string) slog.Handler      { return h }
+
+func TestAsyncHandlerCloseContextStuckWriter(t *testing.T) {
+	stuck := stuckHandler{release: make(chan struct{})}
+	defer close(stuck.release)
+	h := NewAsyncHandler(stuck, 1, OverflowBlock)
+	log := slog.New(h)
+
+	// one record handled (and stuck), one queued and one waiting for room
+	blocked := make(chan error, 1)
+	log.Info("handled")
+	log.Info("queued")
+	go func() {
+		blocked <- h.Handle(context.Background(), slog.NewRecord(time.Now(), slog.LevelInfo, "blocked", 0))
+	}()
+	time.Sleep(50 * time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	closed := make(chan error, 1)
+	go func() { closed <- h.CloseContext(ctx) }()
+
+	select {
+	case err := <-closed:
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("expected deadline exceeded, got %v", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("CloseContext did not return after the deadline")
+	}
+	select {
+	case err := <-blocked:
+		if !errors.Is(err, ErrHandlerClosed) {
+			t.Errorf("expected ErrHandlerClosed for the blocked record, got %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Handle still blocked after Close")
+	}
+}
+
+func TestAsyncHandlerFlushContext(t *testing.T) {
+	stuck := stuckHandler{release: make(chan struct{})}
+	h := NewAsyncHandler(stuck, 10, OverflowBlock)
+	defer h.Close()
+	log := slog.New(h)
+	for i := 0; i < 3; i++ {
+		log.Info("msg")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	err := h.FlushContext(ctx)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected deadline exceeded, got %v", err)
+	}
+	if err.Error() != "3 records not flushed: context deadline exceeded" {
+		t.Errorf("unexpected error %q", err)
+	}
+
+	close(stuck.release)
+	if err := h.FlushContext(context.Background()); err != nil {
+		t.Errorf("expected flushed, got %v", err)
+	}
+}
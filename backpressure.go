@@ -0,0 +1,159 @@
+package slogging
+
+import (
+	"errors"
+	"io"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// records queued by WithDropOnBackpressure for the writer
+const backpressureQueueSize = 256
+
+// counts of a writer with WithDropOnBackpressure
+type BackpressureStats struct {
+	written atomic.Uint64
+	dropped atomic.Uint64
+}
+
+// numbers of records written and dropped, see BackpressureStats.Stats
+type BackpressureCounts struct {
+	Written uint64
+	Dropped uint64
+}
+
+// current counts
+func (s *BackpressureStats) Stats() BackpressureCounts {
+	return BackpressureCounts{Written: s.written.Load(), Dropped: s.dropped.Load()}
+}
+
+// writes records to w from a goroutine, dropping records that can not be
+// queued within timeout, see WithDropOnBackpressure.
+// Close stops the goroutine
+type backpressureWriter struct {
+	w       io.Writer
+	timeout time.Duration
+	stats   *BackpressureStats
+	onError func(error)
+	ch      chan backpressureItem
+	// closed when the goroutine has written all queued records
+	done chan struct{}
+
+	// guards sending on ch against closing it
+	sendMu sync.RWMutex
+	closed bool
+}
+
+// a record, or a flush marker with done
+type backpressureItem struct {
+	p    []byte
+	done chan struct{}
+}
+
+func newBackpressureWriter(w io.Writer, timeout time.Duration, stats *BackpressureStats, onError func(error)) *backpressureWriter {
+	if stats == nil {
+		stats = &BackpressureStats{}
+	}
+	bw := &backpressureWriter{
+		w:       w,
+		timeout: timeout,
+		stats:   stats,
+		onError: onError,
+		ch:      make(chan backpressureItem, backpressureQueueSize),
+		done:    make(chan struct{})}
+	go bw.run()
+	RegisterFlusher(bw)
+	return bw
+}
+
+// queue a copy of p, or drop it if the queue stays full for the timeout.
+// Only fails after Close, as the write happens later
+func (bw *backpressureWriter) Write(p []byte) (int, error) {
+	bw.sendMu.RLock()
+	defer bw.sendMu.RUnlock()
+	if bw.closed {
+		return 0, ErrHandlerClosed
+	}
+
+	item := backpressureItem{p: append([]byte(nil), p...)}
+	select {
+	case bw.ch <- item:
+		return len(p), nil
+	default:
+	}
+
+	t := time.NewTimer(bw.timeout)
+	defer t.Stop()
+	select {
+	case bw.ch <- item:
+	case <-t.C:
+		bw.stats.dropped.Add(1)
+	}
+	return len(p), nil
+}
+
+var errFlushTimeout = errors.New("flush timed out, writer is slow")
+
+// wait for the queued records to be written, at most twice the timeout
+func (bw *backpressureWriter) Flush() error {
+	bw.sendMu.RLock()
+	if bw.closed {
+		bw.sendMu.RUnlock()
+		return nil
+	}
+	done := make(chan struct{})
+	t := time.NewTimer(2 * bw.timeout)
+	defer t.Stop()
+	select {
+	case bw.ch <- backpressureItem{done: done}:
+		bw.sendMu.RUnlock()
+	case <-t.C:
+		bw.sendMu.RUnlock()
+		return errFlushTimeout
+	}
+	select {
+	case <-done:
+		return nil
+	case <-t.C:
+		return errFlushTimeout
+	}
+}
+
+// stop accepting records and wait for the queued records to be written,
+// at most twice the timeout. The goroutine stops when they are written
+func (bw *backpressureWriter) Close() error {
+	DeregisterFlusher(bw)
+	bw.sendMu.Lock()
+	if !bw.closed {
+		bw.closed = true
+		close(bw.ch)
+	}
+	bw.sendMu.Unlock()
+
+	t := time.NewTimer(2 * bw.timeout)
+	defer t.Stop()
+	select {
+	case <-bw.done:
+		return nil
+	case <-t.C:
+		return errFlushTimeout
+	}
+}
+
+func (bw *backpressureWriter) run() {
+	defer close(bw.done)
+	for item := range bw.ch {
+		if item.done != nil {
+			close(item.done)
+			continue
+		}
+		if _, err := bw.w.Write(item.p); err != nil {
+			if bw.onError != nil {
+				bw.onError(err)
+			}
+			continue
+		}
+		bw.stats.written.Add(1)
+	}
+}
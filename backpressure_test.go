@@ -0,0 +1,79 @@
+package slogging
+
+import (
+	"errors"
+	"log/slog"
+	"testing"
+	"time"
+)
+
+// writer blocking until release is closed, counting the writes
+type gatedWriter struct {
+	release chan struct{}
+	writes  chan []byte
+}
+
+func newGatedWriter() *gatedWriter {
+	return &gatedWriter{release: make(chan struct{}), writes: make(chan []byte, 2*backpressureQueueSize)}
+}
+
+func (w *gatedWriter) Write(p []byte) (int, error) {
+	<-w.release
+	w.writes <- p
+	return len(p), nil
+}
+
+func TestDropOnBackpressure(t *testing.T) {
+	w := newGatedWriter()
+	stats := &BackpressureStats{}
+	log, _ := CreateWithOptions(slog.HandlerOptions{Level: slog.LevelInfo}, true,
+		WithWriter(w), WithDropOnBackpressure(time.Millisecond, stats))
+
+	// one record taken by the goroutine, blocked in Write, and the queue full
+	n := backpressureQueueSize + 11
+	for i := 0; i < n; i++ {
+		log.Info("m", "i", i)
+	}
+	close(w.release)
+	if err := Flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	counts := stats.Stats()
+	if counts.Written+counts.Dropped != uint64(n) || counts.Dropped < 10 {
+		t.Errorf("expected %d records written or (at least 10) dropped, got %+v", n, counts)
+	}
+	if len(w.writes) != int(counts.Written) {
+		t.Errorf("expected %d writes, got %d", counts.Written, len(w.writes))
+	}
+}
+
+func TestBackpressureWriterClose(t *testing.T) {
+	w := newGatedWriter()
+	close(w.release)
+	bw := newBackpressureWriter(w, time.Second, nil, nil)
+	for i := 0; i < 3; i++ {
+		_, _ = bw.Write([]byte("m\n"))
+	}
+
+	if err := Close(); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case <-bw.done:
+	default:
+		t.Fatal("expected the goroutine to be stopped")
+	}
+	if len(w.writes) != 3 {
+		t.Errorf("expected queued records written before closing, got %d", len(w.writes))
+	}
+	if _, err := bw.Write([]byte("m\n")); !errors.Is(err, ErrHandlerClosed) {
+		t.Errorf("expected ErrHandlerClosed after Close, got %v", err)
+	}
+	if err := bw.Flush(); err != nil {
+		t.Errorf("expected no error flushing after Close, got %v", err)
+	}
+	if err := bw.Close(); err != nil {
+		t.Errorf("expected no error closing again, got %v", err)
+	}
+}
@@ -0,0 +1,167 @@
+package slogging
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// default thresholds of CreateBatch
+const (
+	DefaultBatchMaxBytes = 64 << 10
+	DefaultBatchMaxDelay = time.Second
+)
+
+// create logger (like Create) formatting records into batches of lines, which
+// are passed to write when the batch reaches maxBytes, when the first record
+// of the batch is maxDelay old, or immediately for ERROR records and above,
+// so alerts are not delayed. maxBytes <= 0 means DefaultBatchMaxBytes and
+// maxDelay <= 0 means DefaultBatchMaxDelay.
+// write is called with one batch at a time and must not keep the slice.
+// An error from write is returned for the next record logged, which is
+// still batched.
+// Close the returned BatchHandler on shutdown to write the last batch.
+// It is registered for the package Flush until closed
+func CreateBatch(write func(batch []byte) error, maxBytes int, maxDelay time.Duration, opts slog.HandlerOptions, jsonOutput bool, attrs ...slog.Attr) (*slog.Logger, http.Handler, *BatchHandler) {
+	if maxBytes <= 0 {
+		maxBytes = DefaultBatchMaxBytes
+	}
+	if maxDelay <= 0 {
+		maxDelay = DefaultBatchMaxDelay
+	}
+	b := &batch{write: write, maxBytes: maxBytes, maxDelay: maxDelay}
+	bh := &BatchHandler{b: b}
+	logger, h := CreateWithOptions(opts, jsonOutput,
+		WithWriter(b),
+		withWrapper(func(next slog.Handler) slog.Handler {
+			bh.next = next
+			return bh
+		}),
+		WithAttrs(attrs...))
+	RegisterFlusher(b)
+	return logger, h, bh
+}
+
+// BatchHandler is a slog.Handler collecting the records formatted by the next
+// handler into batches, see CreateBatch
+type BatchHandler struct {
+	next slog.Handler
+	b    *batch
+}
+
+// shared by a BatchHandler and those derived from it with WithAttrs and WithGroup.
+// Receives the formatted records from the next handler
+type batch struct {
+	write    func([]byte) error
+	maxBytes int
+	maxDelay time.Duration
+
+	mu     sync.Mutex
+	buf    []byte
+	timer  *time.Timer
+	err    error
+	closed bool
+}
+
+func (h *BatchHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+func (h *BatchHandler) Handle(ctx context.Context, r slog.Record) error {
+	if err := h.next.Handle(ctx, r); err != nil {
+		return err
+	}
+	if r.Level >= slog.LevelError {
+		return h.b.Flush()
+	}
+	return nil
+}
+
+func (h *BatchHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &BatchHandler{next: h.next.WithAttrs(attrs), b: h.b}
+}
+
+func (h *BatchHandler) WithGroup(name string) slog.Handler {
+	return &BatchHandler{next: h.next.WithGroup(name), b: h.b}
+}
+
+// write the current batch, if any
+func (h *BatchHandler) Flush() error {
+	return h.b.Flush()
+}
+
+// write the current batch and stop batching. Later records are rejected with
+// ErrHandlerClosed. Applies to all handlers derived from h
+func (h *BatchHandler) Close() error {
+	return h.b.Close()
+}
+
+func (b *batch) Close() error {
+	DeregisterFlusher(b)
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.closed {
+		return nil
+	}
+	err := b.flushLocked()
+	b.closed = true
+	return err
+}
+
+// append a formatted record to the batch. p is always appended (unless
+// closed), so errors from writing earlier batches, also by the timer, are
+// reported without losing p
+func (b *batch) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.closed {
+		return 0, ErrHandlerClosed
+	}
+	// from a flush by the timer
+	errs := []error{b.err}
+	b.err = nil
+	if len(b.buf) > 0 && len(b.buf)+len(p) > b.maxBytes {
+		errs = append(errs, b.flushLocked())
+	}
+	if len(b.buf) == 0 {
+		b.timer = time.AfterFunc(b.maxDelay, b.flushTimer)
+	}
+	b.buf = append(b.buf, p...)
+	if len(b.buf) >= b.maxBytes {
+		errs = append(errs, b.flushLocked())
+	}
+	return len(p), errors.Join(errs...)
+}
+
+func (b *batch) Flush() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.flushLocked()
+}
+
+func (b *batch) flushTimer() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if err := b.flushLocked(); err != nil {
+		b.err = err
+	}
+}
+
+// must be called with mu held
+func (b *batch) flushLocked() error {
+	if b.timer != nil {
+		b.timer.Stop()
+		b.timer = nil
+	}
+	if len(b.buf) == 0 {
+		return nil
+	}
+	err := b.write(b.buf)
+	b.buf = b.buf[:0]
+	return err
+}
@@ -0,0 +1,72 @@
+package slogging
+
+import (
+	"errors"
+	"log/slog"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestBatchKeepsRecordAfterFailedFlush(t *testing.T) {
+	var mu sync.Mutex
+	var batches []string
+	fail := true
+	write := func(batch []byte) error {
+		mu.Lock()
+		defer mu.Unlock()
+		if fail {
+			fail = false
+			return errors.New("unavailable")
+		}
+		batches = append(batches, string(batch))
+		return nil
+	}
+	var errs []error
+	log, _, bh := CreateBatch(write, 0, 10*time.Millisecond, slog.HandlerOptions{Level: slog.LevelInfo}, false)
+	defer bh.Close()
+	log = slog.New(&errorHandler{next: log.Handler(), onError: func(err error) { errs = append(errs, err) }})
+
+	log.Info("lost")
+	// flushed by the timer, failing
+	time.Sleep(50 * time.Millisecond)
+	log.Info("kept")
+	if err := bh.Flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(errs) != 1 || errs[0].Error() != "unavailable" {
+		t.Errorf("expected the timer error reported for the next record, got %v", errs)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if len(batches) != 1 || !strings.Contains(batches[0], "msg=kept") {
+		t.Errorf("expected the next record written, got %q", batches)
+	}
+}
+
+func TestBatchKeepsRecordAfterFailedSizeFlush(t *testing.T) {
+	var batches []string
+	calls := 0
+	write := func(batch []byte) error {
+		calls++
+		if calls == 1 {
+			return errors.New("unavailable")
+		}
+		batches = append(batches, string(batch))
+		return nil
+	}
+	log, _, bh := CreateBatch(write, 100, time.Hour, slog.HandlerOptions{Level: slog.LevelInfo}, false)
+	defer bh.Close()
+
+	log.Info("first", "pad", strings.Repeat("x", 50))
+	// does not fit, so the first batch is written, failing
+	log.Info("second", "pad", strings.Repeat("x", 50))
+	if err := bh.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	if len(batches) != 1 || !strings.Contains(batches[0], "msg=second") {
+		t.Errorf("expected the second record written, got %q", batches)
+	}
+}
@@ -0,0 +1,77 @@
+package slogging
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"sync/atomic"
+	"time"
+)
+
+type budgetContextKey struct{}
+
+// number of records allowed for a context, see ContextWithLogBudget
+type logBudget struct {
+	limit int64
+	count atomic.Int64
+}
+
+// returns ctx with a budget of limit records, after which records logged with
+// the context (or contexts derived from it) are dropped by loggers with
+// WithLogBudget, bar a single "log budget exceeded" WARN record.
+// A budget set on a derived context replaces that of ctx
+func ContextWithLogBudget(ctx context.Context, limit int) context.Context {
+	return context.WithValue(ctx, budgetContextKey{}, &logBudget{limit: int64(limit)})
+}
+
+// http middleware setting a log budget of limit records for each request (see
+// ContextWithLogBudget). Set the budget per route by wrapping the handler of
+// each route, e.g. mux.Handle("/export", LogBudget(10000)(exportHandler)),
+// replacing the budget set for all requests by RequestLoggerWithBudget.
+// Place it inside RequestLogger, so the request itself is always logged
+func LogBudget(limit int) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			next.ServeHTTP(w, r.WithContext(ContextWithLogBudget(r.Context(), limit)))
+		})
+	}
+}
+
+// drops records beyond the budget of their context, see WithLogBudget
+type budgetHandler struct {
+	next slog.Handler
+}
+
+// not enabled when the budget of ctx is spent
+func (h *budgetHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	if b, ok := ctx.Value(budgetContextKey{}).(*logBudget); ok && b.count.Load() > b.limit {
+		return false
+	}
+	return h.next.Enabled(ctx, level)
+}
+
+func (h *budgetHandler) Handle(ctx context.Context, r slog.Record) error {
+	b, ok := ctx.Value(budgetContextKey{}).(*logBudget)
+	if !ok {
+		return h.next.Handle(ctx, r)
+	}
+	n := b.count.Add(1)
+	switch {
+	case n <= b.limit:
+		return h.next.Handle(ctx, r)
+	case n == b.limit+1:
+		nr := slog.NewRecord(time.Now(), slog.LevelWarn, "log budget exceeded", r.PC)
+		nr.AddAttrs(slog.Int64("limit", b.limit))
+		return h.next.Handle(ctx, nr)
+	default:
+		return nil
+	}
+}
+
+func (h *budgetHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &budgetHandler{next: h.next.WithAttrs(attrs)}
+}
+
+func (h *budgetHandler) WithGroup(name string) slog.Handler {
+	return &budgetHandler{next: h.next.WithGroup(name)}
+}
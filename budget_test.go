@@ -0,0 +1,72 @@
+package slogging
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestLogBudget(t *testing.T) {
+	var buf bytes.Buffer
+	log, _ := CreateWithOptions(slog.HandlerOptions{Level: slog.LevelInfo}, false,
+		WithWriter(&buf), WithoutTime(), WithLogBudget())
+
+	ctx := ContextWithLogBudget(context.Background(), 3)
+	for i := 0; i < 10; i++ {
+		log.InfoContext(ctx, "m", "i", i)
+	}
+	log.Info("without budget")
+
+	expected := "level=INFO msg=m i=0\n" +
+		"level=INFO msg=m i=1\n" +
+		"level=INFO msg=m i=2\n" +
+		"level=WARN msg=\"log budget exceeded\" limit=3\n" +
+		"level=INFO msg=\"without budget\"\n"
+	if buf.String() != expected {
+		t.Errorf("expected:\n%s\ngot:\n%s", expected, buf.String())
+	}
+	if log.Enabled(ctx, slog.LevelError) {
+		t.Error("expected not enabled when the budget is spent")
+	}
+}
+
+func TestRequestLoggerWithBudget(t *testing.T) {
+	var buf bytes.Buffer
+	log, _ := CreateWithOptions(slog.HandlerOptions{Level: slog.LevelInfo}, false,
+		WithWriter(&buf), WithoutTime(), WithLogBudget())
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		for i := 0; i < 10; i++ {
+			log.InfoContext(r.Context(), "work")
+		}
+	})
+	mux := http.NewServeMux()
+	mux.Handle("/", handler)
+	mux.Handle("/export", LogBudget(5)(handler))
+	h := RequestLoggerWithBudget(log, 2)(mux)
+
+	for _, tc := range []struct {
+		path     string
+		expected int
+	}{
+		{"/", 2},
+		{"/export", 5},
+	} {
+		buf.Reset()
+		h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, tc.path, nil))
+
+		out := buf.String()
+		if n := strings.Count(out, "msg=work"); n != tc.expected {
+			t.Errorf("%s: expected %d records within the budget, got %d:\n%s", tc.path, tc.expected, n, out)
+		}
+		if n := strings.Count(out, "log budget exceeded"); n != 1 {
+			t.Errorf("%s: expected 1 budget exceeded record, got %d:\n%s", tc.path, n, out)
+		}
+		if !strings.Contains(out, "msg=request method=GET path="+tc.path+" status=200") {
+			t.Errorf("%s: expected the request to be logged, got:\n%s", tc.path, out)
+		}
+	}
+}
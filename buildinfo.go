@@ -0,0 +1,179 @@
+package slogging
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"runtime/debug"
+	"strconv"
+	"strings"
+)
+
+// replaced in tests
+var readBuildInfo = debug.ReadBuildInfo
+
+// log build info (go version and vcs revision, time and modified) to Info level.
+// Returns true if some build info was found.
+// Remember to build the application without specifying the .go file,
+// e.g. "go build -o main", _not_ "go build -o main main.go"
+// See issue https://github.com/golang/go/issues/51279
+func LogBuildInfo() bool {
+	return LogBuildInfoTo(slog.Default())
+}
+
+// like LogBuildInfo, but logs to log
+func LogBuildInfoTo(log *slog.Logger) bool {
+	return LogBuildInfoWith(log, false)
+}
+
+// like LogBuildInfoTo. With warnModified, a build from a modified working tree
+// (vcs.modified=true), which usually is not an official build, is logged at
+// WARN with the message "build info: built from modified source"
+func LogBuildInfoWith(log *slog.Logger, warnModified bool) bool {
+	info, ok := readBuildInfo()
+	if !ok {
+		return false
+	}
+
+	level, msg := slog.LevelInfo, "build info"
+	var attrs []slog.Attr
+	attrs = append(attrs, slog.String("goVersion", info.GoVersion))
+	for _, kv := range vcsSettings(info) {
+		attrs = append(attrs, slog.String(kv.Key, kv.Value))
+		if warnModified && kv.Key == "vcs.modified" && kv.Value == "true" {
+			level, msg = slog.LevelWarn, "build info: built from modified source"
+		}
+	}
+	log.LogAttrs(context.Background(), level, msg, attrs...)
+	return true
+}
+
+// log the module dependencies from the build info to Info level, as a single
+// record with the attribute "deps" holding an array of "path@version", with
+// " => path@version" appended for replaced modules.
+// Returns true if build info was found. Does nothing otherwise
+func LogDependencies(log *slog.Logger) bool {
+	info, ok := readBuildInfo()
+	if !ok {
+		return false
+	}
+
+	deps := make([]string, 0, len(info.Deps))
+	for _, d := range moduleDeps(info) {
+		s := d.Path + "@" + d.Version
+		if d.Replace != "" {
+			s += " => " + d.Replace
+		}
+		deps = append(deps, s)
+	}
+	log.LogAttrs(context.Background(), slog.LevelInfo, "dependencies",
+		slog.String("goVersion", info.GoVersion),
+		slog.Any("deps", deps))
+	return true
+}
+
+// go version and vcs settings (vcs, vcs.revision, vcs.time and vcs.modified)
+// from the build info, without logging.
+// ok is false if no build info is available
+func BuildInfo() (goVersion string, vcs map[string]string, ok bool) {
+	info, ok := readBuildInfo()
+	if !ok {
+		return "", nil, false
+	}
+
+	vcs = make(map[string]string)
+	for _, kv := range vcsSettings(info) {
+		vcs[kv.Key] = kv.Value
+	}
+	return info.GoVersion, vcs, true
+}
+
+func vcsSettings(info *debug.BuildInfo) []debug.BuildSetting {
+	var xs []debug.BuildSetting
+	for _, kv := range info.Settings {
+		if strings.HasPrefix(kv.Key, "vcs") {
+			xs = append(xs, kv)
+		}
+	}
+	return xs
+}
+
+// http.Handler responding to GET (and HEAD) with the build info as JSON:
+//
+//	{"goVersion": "...", "vcs.revision": "...", "vcs.time": "...", "vcs.modified": "..."}
+//
+// With query parameter deps=true, the module dependencies are included as
+// "deps": [{"path": "...", "version": "...", "replace": "..."}].
+// Responds 500 if build info is unavailable
+func BuildInfoHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			w.Header().Set("Allow", "GET, HEAD")
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		info, ok := readBuildInfo()
+		if !ok {
+			w.WriteHeader(http.StatusInternalServerError)
+			_, _ = w.Write([]byte("build info not available"))
+			return
+		}
+
+		resp := buildInfoResponse{GoVersion: info.GoVersion}
+		for _, kv := range info.Settings {
+			switch kv.Key {
+			case "vcs.revision":
+				resp.Revision = kv.Value
+			case "vcs.time":
+				resp.Time = kv.Value
+			case "vcs.modified":
+				resp.Modified = kv.Value
+			}
+		}
+		if deps, _ := strconv.ParseBool(r.URL.Query().Get("deps")); deps {
+			resp.Deps = moduleDeps(info)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(resp)
+	})
+}
+
+type buildInfoResponse struct {
+	GoVersion string      `json:"goVersion"`
+	Revision  string      `json:"vcs.revision,omitempty"`
+	Time      string      `json:"vcs.time,omitempty"`
+	Modified  string      `json:"vcs.modified,omitempty"`
+	Deps      []moduleDep `json:"deps,omitempty"`
+}
+
+type moduleDep struct {
+	Path    string `json:"path"`
+	Version string `json:"version"`
+	Replace string `json:"replace,omitempty"`
+}
+
+func moduleDeps(info *debug.BuildInfo) []moduleDep {
+	xs := make([]moduleDep, 0, len(info.Deps))
+	for _, m := range info.Deps {
+		d := moduleDep{Path: m.Path, Version: m.Version}
+		if m.Replace != nil {
+			d.Replace = m.Replace.Path + "@" + m.Replace.Version
+		}
+		xs = append(xs, d)
+	}
+	return xs
+}
+
+// create logger (like Create) with attributes "service" and "version" attached
+// to every record. The version is the vcs.revision from the build info,
+// or "unknown" if not available
+func CreateWithServiceInfo(service string, opts slog.HandlerOptions, jsonOutput bool, attrs ...slog.Attr) (*slog.Logger, http.Handler) {
+	version := "unknown"
+	if _, vcs, ok := BuildInfo(); ok && vcs["vcs.revision"] != "" {
+		version = vcs["vcs.revision"]
+	}
+	attrs = append([]slog.Attr{slog.String("service", service), slog.String("version", version)}, attrs...)
+	return Create(opts, jsonOutput, attrs...)
+}
@@ -0,0 +1,138 @@
+package slogging
+
+import (
+	"bytes"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"runtime/debug"
+	"testing"
+)
+
+// replace readBuildInfo with info until the test ends. nil means not available
+func fakeBuildInfo(t *testing.T, info *debug.BuildInfo) {
+	t.Helper()
+	prev := readBuildInfo
+	t.Cleanup(func() { readBuildInfo = prev })
+	readBuildInfo = func() (*debug.BuildInfo, bool) { return info, info != nil }
+}
+
+func testBuildInfo(modified string) *debug.BuildInfo {
+	return &debug.BuildInfo{
+		GoVersion: "go1.22.1",
+		Main:      debug.Module{Path: "example.com/app"},
+		Settings: []debug.BuildSetting{
+			{Key: "-compiler", Value: "gc"},
+			{Key: "vcs", Value: "git"},
+			{Key: "vcs.revision", Value: "abc123"},
+			{Key: "vcs.time", Value: "2024-05-01T12:00:00Z"},
+			{Key: "vcs.modified", Value: modified}}}
+}
+
+func TestLogBuildInfoWithWarnModified(t *testing.T) {
+	const attrs = " goVersion=go1.22.1 vcs=git vcs.revision=abc123 vcs.time=2024-05-01T12:00:00Z"
+	for _, tc := range []struct {
+		name         string
+		modified     string
+		warnModified bool
+		expected     string
+	}{
+		{"clean", "false", true, `level=INFO msg="build info"` + attrs + " vcs.modified=false\n"},
+		{"modified", "true", true, `level=WARN msg="build info: built from modified source"` + attrs + " vcs.modified=true\n"},
+		{"modified without warn", "true", false, `level=INFO msg="build info"` + attrs + " vcs.modified=true\n"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			fakeBuildInfo(t, testBuildInfo(tc.modified))
+			var buf bytes.Buffer
+			log, _ := CreateWithOptions(slog.HandlerOptions{Level: slog.LevelInfo}, false,
+				WithWriter(&buf), WithoutTime())
+
+			if !LogBuildInfoWith(log, tc.warnModified) {
+				t.Fatal("expected build info found")
+			}
+			if buf.String() != tc.expected {
+				t.Errorf("expected:\n%s\ngot:\n%s", tc.expected, buf.String())
+			}
+		})
+	}
+}
+
+func TestBuildInfo(t *testing.T) {
+	fakeBuildInfo(t, testBuildInfo("false"))
+
+	goVersion, vcs, ok := BuildInfo()
+	if !ok || goVersion != "go1.22.1" {
+		t.Fatalf("expected go1.22.1, got %q (ok %v)", goVersion, ok)
+	}
+	expected := map[string]string{
+		"vcs":          "git",
+		"vcs.revision": "abc123",
+		"vcs.time":     "2024-05-01T12:00:00Z",
+		"vcs.modified": "false"}
+	if len(vcs) != len(expected) {
+		t.Errorf("expected %v, got %v", expected, vcs)
+	}
+	for k, v := range expected {
+		if vcs[k] != v {
+			t.Errorf("expected %s=%s, got %q", k, v, vcs[k])
+		}
+	}
+}
+
+func TestBuildInfoNotAvailable(t *testing.T) {
+	fakeBuildInfo(t, nil)
+	var buf bytes.Buffer
+	log, _ := CreateWithOptions(slog.HandlerOptions{Level: slog.LevelInfo}, false, WithWriter(&buf))
+
+	if _, _, ok := BuildInfo(); ok {
+		t.Error("expected no build info")
+	}
+	if LogBuildInfoTo(log) || LogDependencies(log) {
+		t.Error("expected false without build info")
+	}
+	if buf.Len() != 0 {
+		t.Errorf("expected nothing logged, got %s", buf.String())
+	}
+}
+
+func TestLogBuildInfoTo(t *testing.T) {
+	fakeBuildInfo(t, testBuildInfo("false"))
+	var buf bytes.Buffer
+	log, _ := CreateWithOptions(slog.HandlerOptions{Level: slog.LevelInfo}, true, WithWriter(&buf), WithoutTime())
+
+	if !LogBuildInfoTo(log) {
+		t.Fatal("expected build info found")
+	}
+	expected := `{"level":"INFO","msg":"build info","goVersion":"go1.22.1","vcs":"git","vcs.revision":"abc123",` +
+		`"vcs.time":"2024-05-01T12:00:00Z","vcs.modified":"false"}` + "\n"
+	if buf.String() != expected {
+		t.Errorf("expected:\n%s\ngot:\n%s", expected, buf.String())
+	}
+}
+
+func TestBuildInfoHandler(t *testing.T) {
+	fakeBuildInfo(t, testBuildInfo("true"))
+	h := BuildInfoHandler()
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/version", nil))
+	expected := `{"goVersion":"go1.22.1","vcs.revision":"abc123","vcs.time":"2024-05-01T12:00:00Z","vcs.modified":"true"}` + "\n"
+	if w.Code != http.StatusOK || w.Body.String() != expected {
+		t.Errorf("expected 200 with %s, got %d with %s", expected, w.Code, w.Body)
+	}
+
+	for _, method := range []string{http.MethodPut, http.MethodPost, http.MethodDelete} {
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest(method, "/version", nil))
+		if w.Code != http.StatusMethodNotAllowed || w.Header().Get("Allow") != "GET, HEAD" {
+			t.Errorf("%s: expected 405 with Allow: GET, HEAD, got %d with %q", method, w.Code, w.Header().Get("Allow"))
+		}
+	}
+
+	fakeBuildInfo(t, nil)
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodHead, "/version", nil))
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("expected 500 without build info, got %d", w.Code)
+	}
+}
@@ -0,0 +1,51 @@
+package slogging
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+)
+
+// how Bytes encodes byte slices
+type ByteEncoding int
+
+const (
+	EncodeHex ByteEncoding = iota
+	EncodeBase64
+)
+
+// package defaults for Bytes. Set during initialization, before logging
+var (
+	DefaultByteEncoding = EncodeHex
+	// max number of bytes rendered by Bytes
+	DefaultBytesMaxLen = 256
+)
+
+// attribute rendering b as a string with DefaultByteEncoding, truncated to
+// DefaultBytesMaxLen bytes with a "…(N more)" suffix.
+// Use instead of slog.Any for raw bytes, which may produce huge or invalid output
+func Bytes(key string, b []byte) slog.Attr {
+	return BytesWith(key, b, DefaultByteEncoding, DefaultBytesMaxLen)
+}
+
+// like Bytes with the encoding and max length specified. maxLen <= 0 means no limit
+func BytesWith(key string, b []byte, enc ByteEncoding, maxLen int) slog.Attr {
+	more := 0
+	if maxLen > 0 && len(b) > maxLen {
+		more = len(b) - maxLen
+		b = b[:maxLen]
+	}
+
+	var s string
+	switch enc {
+	case EncodeBase64:
+		s = base64.StdEncoding.EncodeToString(b)
+	default:
+		s = hex.EncodeToString(b)
+	}
+	if more > 0 {
+		s += fmt.Sprintf("…(%d more)", more)
+	}
+	return slog.String(key, s)
+}
@@ -0,0 +1,60 @@
+package slogging
+
+import (
+	"context"
+	"log/slog"
+	"runtime"
+)
+
+// attribute key of the caller function, see WithCallerFunc
+const CallerFuncKey = "func"
+
+// adds the fully-qualified function name of the record PC with key CallerFuncKey
+// at the top level, see WithCallerFunc
+type callerFuncHandler struct {
+	next slog.Handler
+	goas []groupOrAttrs
+}
+
+func (h *callerFuncHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+func (h *callerFuncHandler) Handle(ctx context.Context, r slog.Record) error {
+	if r.PC == 0 {
+		return h.next.Handle(ctx, r)
+	}
+	fs := runtime.CallersFrames([]uintptr{r.PC})
+	f, _ := fs.Next()
+	if f.Function == "" {
+		return h.next.Handle(ctx, r)
+	}
+	fn := slog.String(CallerFuncKey, f.Function)
+
+	if len(h.goas) == 0 {
+		r = r.Clone()
+		r.AddAttrs(fn)
+		return h.next.Handle(ctx, r)
+	}
+	nr := slog.NewRecord(r.Time, r.Level, r.Message, r.PC)
+	nr.AddAttrs(fn)
+	nr.AddAttrs(nestAttrs(h.goas, recordAttrs(r))...)
+	return h.next.Handle(ctx, nr)
+}
+
+func (h *callerFuncHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	if len(attrs) == 0 {
+		return h
+	}
+	if len(h.goas) == 0 {
+		return &callerFuncHandler{next: h.next.WithAttrs(attrs)}
+	}
+	return &callerFuncHandler{next: h.next, goas: appendGroupOrAttrs(h.goas, groupOrAttrs{attrs: attrs})}
+}
+
+func (h *callerFuncHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	return &callerFuncHandler{next: h.next, goas: appendGroupOrAttrs(h.goas, groupOrAttrs{group: name})}
+}
@@ -0,0 +1,143 @@
+// Package capture provides a slog.Handler recording log records in memory,
+// for assertions in tests.
+package capture
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"testing"
+)
+
+// Handler records every record (at any level) in memory.
+// Attributes and groups from WithAttrs and WithGroup are included in the
+// recorded records. It is safe for concurrent use.
+// Handlers derived with WithAttrs and WithGroup share the recorded records.
+type Handler struct {
+	s    *store
+	goas []groupOrAttrs
+}
+
+type store struct {
+	mu      sync.Mutex
+	records []slog.Record
+}
+
+type groupOrAttrs struct {
+	group string
+	attrs []slog.Attr
+}
+
+// create logger recording to the returned handler
+func New() (*slog.Logger, *Handler) {
+	h := &Handler{s: &store{}}
+	return slog.New(h), h
+}
+
+func (h *Handler) Enabled(context.Context, slog.Level) bool {
+	return true
+}
+
+func (h *Handler) Handle(_ context.Context, r slog.Record) error {
+	nr := slog.NewRecord(r.Time, r.Level, r.Message, r.PC)
+	var attrs []slog.Attr
+	r.Attrs(func(a slog.Attr) bool {
+		attrs = append(attrs, a)
+		return true
+	})
+	for i := len(h.goas) - 1; i >= 0; i-- {
+		g := h.goas[i]
+		if g.group == "" {
+			attrs = append(append([]slog.Attr{}, g.attrs...), attrs...)
+			continue
+		}
+		if len(attrs) > 0 {
+			attrs = []slog.Attr{{Key: g.group, Value: slog.GroupValue(attrs...)}}
+		}
+	}
+	nr.AddAttrs(attrs...)
+
+	h.s.mu.Lock()
+	defer h.s.mu.Unlock()
+	h.s.records = append(h.s.records, nr)
+	return nil
+}
+
+func (h *Handler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	if len(attrs) == 0 {
+		return h
+	}
+	return &Handler{s: h.s, goas: append(h.goas[:len(h.goas):len(h.goas)], groupOrAttrs{attrs: attrs})}
+}
+
+func (h *Handler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	return &Handler{s: h.s, goas: append(h.goas[:len(h.goas):len(h.goas)], groupOrAttrs{group: name})}
+}
+
+// recorded records, oldest first
+func (h *Handler) Records() []slog.Record {
+	h.s.mu.Lock()
+	defer h.s.mu.Unlock()
+
+	xs := make([]slog.Record, len(h.s.records))
+	for i, r := range h.s.records {
+		xs[i] = r.Clone()
+	}
+	return xs
+}
+
+// message of the last record. Empty if none
+func (h *Handler) LastMessage() string {
+	h.s.mu.Lock()
+	defer h.s.mu.Unlock()
+
+	if len(h.s.records) == 0 {
+		return ""
+	}
+	return h.s.records[len(h.s.records)-1].Message
+}
+
+// discard recorded records
+func (h *Handler) Reset() {
+	h.s.mu.Lock()
+	defer h.s.mu.Unlock()
+	h.s.records = nil
+}
+
+// whether a record with level and message has been recorded, having all of
+// attrs at the top level (compared with slog.Attr.Equal after resolving values)
+func (h *Handler) Contains(level slog.Level, msg string, attrs ...slog.Attr) bool {
+	for _, r := range h.Records() {
+		if r.Level == level && r.Message == msg && hasAttrs(r, attrs) {
+			return true
+		}
+	}
+	return false
+}
+
+// fail t if Contains returns false
+func (h *Handler) AssertContains(t testing.TB, level slog.Level, msg string, attrs ...slog.Attr) {
+	t.Helper()
+	if !h.Contains(level, msg, attrs...) {
+		t.Errorf("no record with level %s, message %q and attributes %v", level, msg, attrs)
+	}
+}
+
+func hasAttrs(r slog.Record, attrs []slog.Attr) bool {
+	for _, want := range attrs {
+		want.Value = want.Value.Resolve()
+		found := false
+		r.Attrs(func(a slog.Attr) bool {
+			a.Value = a.Value.Resolve()
+			found = a.Equal(want)
+			return !found
+		})
+		if !found {
+			return false
+		}
+	}
+	return true
+}
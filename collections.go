@@ -0,0 +1,68 @@
+package slogging
+
+import (
+	"cmp"
+	"fmt"
+	"log/slog"
+	"reflect"
+	"slices"
+)
+
+// attribute rendering the slice or array v with at most maxItems elements,
+// followed by a "…(N more)" element if any were left out.
+// maxItems <= 0 means no limit. Other values are logged as with slog.Any
+func SliceAttr(key string, v any, maxItems int) slog.Attr {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+		return slog.Any(key, v)
+	}
+
+	n := rv.Len()
+	if maxItems <= 0 || maxItems > n {
+		maxItems = n
+	}
+	xs := make([]any, 0, maxItems+1)
+	for i := 0; i < maxItems; i++ {
+		xs = append(xs, rv.Index(i).Interface())
+	}
+	if more := n - maxItems; more > 0 {
+		xs = append(xs, fmt.Sprintf("…(%d more)", more))
+	}
+	return slog.Any(key, xs)
+}
+
+// attribute rendering the map v with at most maxItems entries, with keys
+// formatted as strings and chosen in sorted order, so the output is stable.
+// If entries were left out, an entry with key "…" and value "(N more)" is added.
+// maxItems <= 0 means no limit. Other values are logged as with slog.Any
+func MapAttr(key string, v any, maxItems int) slog.Attr {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Map {
+		return slog.Any(key, v)
+	}
+
+	type entry struct {
+		key string
+		v   reflect.Value
+	}
+	entries := make([]entry, 0, rv.Len())
+	for it := rv.MapRange(); it.Next(); {
+		entries = append(entries, entry{key: fmt.Sprint(it.Key().Interface()), v: it.Value()})
+	}
+	slices.SortFunc(entries, func(a, b entry) int {
+		return cmp.Compare(a.key, b.key)
+	})
+
+	n := len(entries)
+	if maxItems <= 0 || maxItems > n {
+		maxItems = n
+	}
+	m := make(map[string]any, maxItems+1)
+	for _, e := range entries[:maxItems] {
+		m[e.key] = e.v.Interface()
+	}
+	if more := n - maxItems; more > 0 {
+		m["…"] = fmt.Sprintf("(%d more)", more)
+	}
+	return slog.Any(key, m)
+}
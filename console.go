@@ -0,0 +1,253 @@
+package slogging
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+)
+
+// whether to use colored console output for text output, see WithColor
+type ColorMode int
+
+const (
+	// color when the writer is a terminal and NO_COLOR is not set
+	ColorAuto ColorMode = iota
+	ColorOn
+	ColorOff
+)
+
+// ANSI escape codes
+const (
+	ansiReset   = "\x1b[0m"
+	ansiDim     = "\x1b[2m"
+	ansiRed     = "\x1b[31m"
+	ansiBoldRed = "\x1b[1;31m"
+	ansiGreen   = "\x1b[32m"
+	ansiYellow  = "\x1b[33m"
+	ansiMagenta = "\x1b[35m"
+	ansiCyan    = "\x1b[36m"
+)
+
+// whether to color output to w with mode
+func (m ColorMode) enabled(w io.Writer) bool {
+	switch m {
+	case ColorOn:
+		return true
+	case ColorOff:
+		return false
+	default:
+		return os.Getenv("NO_COLOR") == "" && isTerminal(w)
+	}
+}
+
+// whether w is an *os.File connected to a terminal
+func isTerminal(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
+
+// ConsoleHandler is a slog.Handler writing human-friendly, colored lines
+// for local development: dimmed time, colored and aligned level, message
+// and then key=value attributes.
+// The options are applied as for slog.TextHandler.
+type ConsoleHandler struct {
+	opts slog.HandlerOptions
+	mu   *sync.Mutex
+	w    io.Writer
+
+	// attributes from WithAttrs, already formatted
+	preformatted []byte
+	// groups from WithGroup
+	groups []string
+}
+
+// create ConsoleHandler writing to w. opts may be nil
+func NewConsoleHandler(w io.Writer, opts *slog.HandlerOptions) *ConsoleHandler {
+	h := &ConsoleHandler{mu: &sync.Mutex{}, w: w}
+	if opts != nil {
+		h.opts = *opts
+	}
+	return h
+}
+
+func (h *ConsoleHandler) Enabled(_ context.Context, level slog.Level) bool {
+	minLevel := slog.LevelInfo
+	if h.opts.Level != nil {
+		minLevel = h.opts.Level.Level()
+	}
+	return level >= minLevel
+}
+
+func (h *ConsoleHandler) Handle(_ context.Context, r slog.Record) error {
+	var buf []byte
+
+	if !r.Time.IsZero() {
+		if a, ok := h.replaceBuiltin(slog.Time(slog.TimeKey, r.Time)); ok {
+			s := a.Value.String()
+			if a.Value.Kind() == slog.KindTime {
+				s = a.Value.Time().Format(time.TimeOnly + ".000")
+			}
+			buf = append(buf, ansiDim+s+ansiReset+" "...)
+		}
+	}
+
+	if a, ok := h.replaceBuiltin(slog.Any(slog.LevelKey, r.Level)); ok {
+		buf = append(buf, levelColor(r.Level)...)
+		buf = append(buf, fmt.Sprintf("%-5s", a.Value.String())...)
+		buf = append(buf, ansiReset+" "...)
+	}
+
+	if h.opts.AddSource && r.PC != 0 {
+		fs := runtime.CallersFrames([]uintptr{r.PC})
+		f, _ := fs.Next()
+		// as *slog.Source, like the slog handlers, so ReplaceAttr (e.g. SourceTrim) can change it
+		src := slog.Any(slog.SourceKey, &slog.Source{Function: f.Function, File: f.File, Line: f.Line})
+		if a, ok := h.replaceBuiltin(src); ok {
+			s := a.Value.String()
+			if src, ok := a.Value.Any().(*slog.Source); ok && src != nil {
+				s = fmt.Sprintf("%s:%d", src.File, src.Line)
+			}
+			buf = append(buf, ansiDim+s+ansiReset+" "...)
+		}
+	}
+
+	if a, ok := h.replaceBuiltin(slog.String(slog.MessageKey, r.Message)); ok {
+		buf = append(buf, quoteMessage(a.Value.String())...)
+	}
+
+	buf = append(buf, h.preformatted...)
+	prefix := groupPrefix(h.groups)
+	r.Attrs(func(a slog.Attr) bool {
+		buf = h.appendAttr(buf, prefix, h.groups, a)
+		return true
+	})
+	buf = append(buf, '\n')
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	_, err := h.w.Write(buf)
+	return err
+}
+
+func (h *ConsoleHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	if len(attrs) == 0 {
+		return h
+	}
+	h2 := *h
+	h2.preformatted = append([]byte{}, h.preformatted...)
+	prefix := groupPrefix(h.groups)
+	for _, a := range attrs {
+		h2.preformatted = h.appendAttr(h2.preformatted, prefix, h.groups, a)
+	}
+	return &h2
+}
+
+func (h *ConsoleHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	h2 := *h
+	h2.groups = append(h.groups[:len(h.groups):len(h.groups)], name)
+	return &h2
+}
+
+// apply ReplaceAttr to a built-in attribute. Returns false if it was dropped
+func (h *ConsoleHandler) replaceBuiltin(a slog.Attr) (slog.Attr, bool) {
+	if h.opts.ReplaceAttr == nil {
+		return a, true
+	}
+	a = h.opts.ReplaceAttr(nil, a)
+	return a, !a.Equal(slog.Attr{})
+}
+
+func (h *ConsoleHandler) appendAttr(buf []byte, prefix string, groups []string, a slog.Attr) []byte {
+	a.Value = a.Value.Resolve()
+	if a.Value.Kind() == slog.KindGroup {
+		attrs := a.Value.Group()
+		if len(attrs) == 0 {
+			return buf
+		}
+		if a.Key != "" {
+			prefix += a.Key + "."
+			groups = append(groups[:len(groups):len(groups)], a.Key)
+		}
+		for _, x := range attrs {
+			buf = h.appendAttr(buf, prefix, groups, x)
+		}
+		return buf
+	}
+
+	if h.opts.ReplaceAttr != nil {
+		a = h.opts.ReplaceAttr(groups, a)
+		if a.Equal(slog.Attr{}) {
+			return buf
+		}
+		a.Value = a.Value.Resolve()
+	}
+	buf = append(buf, ' ')
+	buf = append(buf, ansiDim+prefix+a.Key+"="+ansiReset...)
+	return append(buf, quoteIfNeeded(a.Value.String())...)
+}
+
+func groupPrefix(groups []string) string {
+	if len(groups) == 0 {
+		return ""
+	}
+	return strings.Join(groups, ".") + "."
+}
+
+func quoteIfNeeded(s string) string {
+	if s == "" {
+		return `""`
+	}
+	for _, r := range s {
+		if unicode.IsSpace(r) || r == '"' || r == '=' || !unicode.IsPrint(r) {
+			return strconv.Quote(s)
+		}
+	}
+	return s
+}
+
+// quote the message if it contains control or other non-printable characters,
+// so e.g. a newline in the message cannot forge another log line.
+// Unlike quoteIfNeeded, spaces are kept as is
+func quoteMessage(s string) string {
+	for _, r := range s {
+		if !unicode.IsPrint(r) {
+			return strconv.Quote(s)
+		}
+	}
+	return s
+}
+
+func levelColor(l slog.Level) string {
+	switch {
+	case l > slog.LevelError:
+		return ansiBoldRed
+	case l >= slog.LevelError:
+		return ansiRed
+	case l >= slog.LevelWarn:
+		return ansiYellow
+	case l >= slog.LevelInfo:
+		return ansiGreen
+	case l >= slog.LevelDebug:
+		return ansiCyan
+	default:
+		return ansiMagenta
+	}
+}
@@ -0,0 +1,56 @@
+package slogging
+
+import (
+	"bytes"
+	"log/slog"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestConsoleHandlerSourceTrim(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		options  []Option
+		expected *regexp.Regexp
+	}{
+		{"untrimmed", nil, regexp.MustCompile(`\x1b\[2m/\S+/console_test.go:\d+\x1b\[0m `)},
+		{"base", []Option{WithSourceTrim(SourceTrimBase)}, regexp.MustCompile(`\x1b\[2mconsole_test.go:\d+\x1b\[0m `)},
+		{"module", []Option{WithSourceTrim(SourceTrimModule)}, regexp.MustCompile(`\x1b\[2mconsole_test.go:\d+\x1b\[0m `)},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			opts := slog.HandlerOptions{Level: slog.LevelInfo, AddSource: true}
+			options := append([]Option{WithWriter(&buf), WithColor(ColorOn)}, tc.options...)
+			log, _ := CreateWithOptions(opts, false, options...)
+
+			log.Info("m")
+
+			if !tc.expected.Match(buf.Bytes()) {
+				t.Errorf("expected source matching %s, got %q", tc.expected, buf.Bytes())
+			}
+		})
+	}
+}
+
+func TestConsoleHandlerMessageEscaped(t *testing.T) {
+	for _, tc := range []struct {
+		msg, expected string
+	}{
+		{"plain message", " plain message\n"},
+		{"forged\nINFO line", ` "forged\nINFO line"` + "\n"},
+		{"bell\a", ` "bell\a"` + "\n"},
+		{"tab\tseparated", ` "tab\tseparated"` + "\n"},
+	} {
+		t.Run(tc.msg, func(t *testing.T) {
+			var buf bytes.Buffer
+			log := slog.New(NewConsoleHandler(&buf, nil))
+
+			log.Info(tc.msg)
+
+			if s := buf.String(); !strings.HasSuffix(s, tc.expected) || strings.Count(s, "\n") != 1 {
+				t.Errorf("expected line ending with %q, got %q", tc.expected, s)
+			}
+		})
+	}
+}
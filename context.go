@@ -0,0 +1,191 @@
+package slogging
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+	"sync"
+)
+
+var (
+	contextValuesMu sync.RWMutex
+	contextValues   []contextValue
+	contextFuncs    []func(context.Context) []slog.Attr
+)
+
+type contextValue struct {
+	key     any
+	attrKey string
+}
+
+// register a context key, so that ContextHandler adds the value of ctx.Value(key)
+// as an attribute named attrKey, when present.
+// Registering the same key again replaces the attribute name
+func RegisterContextValue(key any, attrKey string) {
+	contextValuesMu.Lock()
+	defer contextValuesMu.Unlock()
+
+	for i, x := range contextValues {
+		if x.key == key {
+			contextValues[i].attrKey = attrKey
+			return
+		}
+	}
+	contextValues = append(contextValues, contextValue{key: key, attrKey: attrKey})
+}
+
+// register f, so that ContextHandler adds the attributes returned by f(ctx).
+// Use to derive attributes from the context, e.g. trace IDs
+func RegisterContextFunc(f func(ctx context.Context) []slog.Attr) {
+	contextValuesMu.Lock()
+	defer contextValuesMu.Unlock()
+	contextFuncs = append(contextFuncs, f)
+}
+
+type attrsContextKey struct{}
+
+// returns ctx with attrs added to those of ctx, which ContextHandler adds to
+// each record logged with the context (after those of RegisterContextValue and
+// RegisterContextFunc). Nested calls accumulate; for duplicate keys the last
+// (innermost) value wins, keeping the position of the first
+func ContextWithAttrs(ctx context.Context, attrs ...slog.Attr) context.Context {
+	if len(attrs) == 0 {
+		return ctx
+	}
+	prev := AttrsFromContext(ctx)
+	merged := make([]slog.Attr, len(prev), len(prev)+len(attrs))
+	copy(merged, prev)
+	for _, a := range attrs {
+		i := slices.IndexFunc(merged, func(x slog.Attr) bool { return x.Key == a.Key })
+		if i >= 0 {
+			merged[i] = a
+		} else {
+			merged = append(merged, a)
+		}
+	}
+	return context.WithValue(ctx, attrsContextKey{}, merged)
+}
+
+// attributes added with ContextWithAttrs, if any. Must not be modified
+func AttrsFromContext(ctx context.Context) []slog.Attr {
+	attrs, _ := ctx.Value(attrsContextKey{}).([]slog.Attr)
+	return attrs
+}
+
+// ContextHandler is a slog.Handler adding attributes from the context
+// (see RegisterContextValue, RegisterContextFunc and ContextWithAttrs) to each record before passing it to the next handler.
+// The attributes are added at the top level, also when groups are used.
+type ContextHandler struct {
+	// next with attributes up to the first group applied
+	next slog.Handler
+	// groups and attributes after (and including) the first group
+	goas []groupOrAttrs
+}
+
+type groupOrAttrs struct {
+	group string
+	attrs []slog.Attr
+}
+
+// wrap next in a ContextHandler
+func NewContextHandler(next slog.Handler) *ContextHandler {
+	return &ContextHandler{next: next}
+}
+
+// if ctx has a level (see ContextWithLevel), it is used instead of the level of next
+func (h *ContextHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	if minLevel, ok := LevelFromContext(ctx); ok {
+		return level >= minLevel
+	}
+	return h.next.Enabled(ctx, level)
+}
+
+func (h *ContextHandler) Handle(ctx context.Context, r slog.Record) error {
+	attrs := contextAttrs(ctx)
+	if len(attrs) == 0 && len(h.goas) == 0 {
+		return h.next.Handle(ctx, r)
+	}
+
+	// rebuild the record, with the context attributes at the top level
+	// and the record attributes nested in the groups
+	nr := slog.NewRecord(r.Time, r.Level, r.Message, r.PC)
+	nr.AddAttrs(attrs...)
+	nr.AddAttrs(nestAttrs(h.goas, recordAttrs(r))...)
+	return h.next.Handle(ctx, nr)
+}
+
+func (h *ContextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	if len(attrs) == 0 {
+		return h
+	}
+	if len(h.goas) == 0 {
+		return &ContextHandler{next: h.next.WithAttrs(attrs)}
+	}
+	return &ContextHandler{next: h.next, goas: appendGroupOrAttrs(h.goas, groupOrAttrs{attrs: attrs})}
+}
+
+func (h *ContextHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	return &ContextHandler{next: h.next, goas: appendGroupOrAttrs(h.goas, groupOrAttrs{group: name})}
+}
+
+// attributes for the registered context values present in ctx,
+// from the registered context funcs and from ContextWithAttrs
+func contextAttrs(ctx context.Context) []slog.Attr {
+	contextValuesMu.RLock()
+	defer contextValuesMu.RUnlock()
+
+	var attrs []slog.Attr
+	for _, x := range contextValues {
+		if v := ctx.Value(x.key); v != nil {
+			attrs = append(attrs, slog.Any(x.attrKey, v))
+		}
+	}
+	for _, f := range contextFuncs {
+		attrs = append(attrs, f(ctx)...)
+	}
+	return append(attrs, AttrsFromContext(ctx)...)
+}
+
+func recordAttrs(r slog.Record) []slog.Attr {
+	attrs := make([]slog.Attr, 0, r.NumAttrs())
+	r.Attrs(func(a slog.Attr) bool {
+		attrs = append(attrs, a)
+		return true
+	})
+	return attrs
+}
+
+// nest attrs in the groups of goas, with the attributes of goas preceding
+func nestAttrs(goas []groupOrAttrs, attrs []slog.Attr) []slog.Attr {
+	for i := len(goas) - 1; i >= 0; i-- {
+		g := goas[i]
+		if g.group == "" {
+			attrs = append(append([]slog.Attr{}, g.attrs...), attrs...)
+			continue
+		}
+		attrs = []slog.Attr{{Key: g.group, Value: slog.GroupValue(attrs...)}}
+	}
+	return attrs
+}
+
+// append without aliasing the backing array of goas
+func appendGroupOrAttrs(goas []groupOrAttrs, x groupOrAttrs) []groupOrAttrs {
+	return append(goas[:len(goas):len(goas)], x)
+}
+
+type levelContextKey struct{}
+
+// returns ctx with a minimum level, used by ContextHandler instead of the
+// level of the logger for records logged with the context
+func ContextWithLevel(ctx context.Context, level slog.Level) context.Context {
+	return context.WithValue(ctx, levelContextKey{}, level)
+}
+
+// level set with ContextWithLevel, if any
+func LevelFromContext(ctx context.Context) (slog.Level, bool) {
+	lvl, ok := ctx.Value(levelContextKey{}).(slog.Level)
+	return lvl, ok
+}
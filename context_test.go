@@ -0,0 +1,52 @@
+package slogging
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"testing"
+)
+
+func TestContextWithAttrs(t *testing.T) {
+	var buf bytes.Buffer
+	log, _ := CreateWithOptions(slog.HandlerOptions{Level: slog.LevelInfo}, true,
+		WithWriter(&buf), WithoutTime(), WithContextHandler())
+
+	outer := ContextWithAttrs(context.Background(), slog.String("user_id", "u1"), slog.Int("n", 1))
+	inner := ContextWithAttrs(outer, slog.Int("n", 2), slog.String("request_id", "r1"))
+	innermost := ContextWithAttrs(inner, slog.Int("n", 3))
+
+	for _, tc := range []struct {
+		name     string
+		ctx      context.Context
+		expected string
+	}{
+		{"none", context.Background(), `{"level":"INFO","msg":"m","a":1}`},
+		{"outer", outer, `{"level":"INFO","msg":"m","user_id":"u1","n":1,"a":1}`},
+		// last wins, in the position of the first
+		{"inner", inner, `{"level":"INFO","msg":"m","user_id":"u1","n":2,"request_id":"r1","a":1}`},
+		{"innermost", innermost, `{"level":"INFO","msg":"m","user_id":"u1","n":3,"request_id":"r1","a":1}`},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			buf.Reset()
+			log.InfoContext(tc.ctx, "m", "a", 1)
+			if expected := tc.expected + "\n"; buf.String() != expected {
+				t.Errorf("expected %s, got %s", expected, buf.String())
+			}
+		})
+	}
+
+	t.Run("outer unchanged", func(t *testing.T) {
+		attrs := AttrsFromContext(outer)
+		if len(attrs) != 2 || attrs[1].Value.Int64() != 1 {
+			t.Errorf("expected outer attributes unchanged, got %v", attrs)
+		}
+	})
+	t.Run("top level in group", func(t *testing.T) {
+		buf.Reset()
+		log.WithGroup("g").InfoContext(outer, "m", "a", 1)
+		if expected := `{"level":"INFO","msg":"m","user_id":"u1","n":1,"g":{"a":1}}` + "\n"; buf.String() != expected {
+			t.Errorf("expected %s, got %s", expected, buf.String())
+		}
+	})
+}
@@ -0,0 +1,38 @@
+package slogging
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// attribute key of Deadline
+const DeadlineRemainingKey = "deadline_remaining_ms"
+
+// attribute with the milliseconds left until the deadline of ctx, negative
+// when it has passed. Returns the empty Attr, which is omitted, when ctx has
+// no deadline
+func Deadline(ctx context.Context) slog.Attr {
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		return slog.Attr{}
+	}
+	return slog.Int64(DeadlineRemainingKey, time.Until(deadline).Milliseconds())
+}
+
+// register Deadline with RegisterContextFunc, so loggers with a ContextHandler
+// (e.g. with the WithContextHandler option) add it to each record logged with
+// a context with a deadline. Calling it more than once has no effect
+func RegisterDeadline() {
+	registerDeadlineOnce.Do(func() {
+		RegisterContextFunc(func(ctx context.Context) []slog.Attr {
+			if a := Deadline(ctx); a.Key != "" {
+				return []slog.Attr{a}
+			}
+			return nil
+		})
+	})
+}
+
+var registerDeadlineOnce sync.Once
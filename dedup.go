@@ -0,0 +1,100 @@
+package slogging
+
+import (
+	"context"
+	"log/slog"
+)
+
+// which attribute NewDedupHandler keeps for duplicate keys
+const (
+	DedupKeepFirst = "first"
+	DedupKeepLast  = "last"
+)
+
+// DedupHandler is a slog.Handler collapsing attributes with duplicate keys
+// within a record (from WithAttrs and the record itself) before passing
+// it to the next handler. Keys are compared within each group, and groups
+// with the same key are merged.
+// Attributes from WithAttrs are kept by the DedupHandler and passed in
+// each record, so the next handler can not preformat them.
+type DedupHandler struct {
+	next     slog.Handler
+	keepLast bool
+	goas     []groupOrAttrs
+}
+
+// create DedupHandler keeping the first (DedupKeepFirst) or last (DedupKeepLast)
+// attribute for duplicate keys. Any other keep is treated as DedupKeepLast
+func NewDedupHandler(next slog.Handler, keep string) *DedupHandler {
+	return &DedupHandler{next: next, keepLast: keep != DedupKeepFirst}
+}
+
+func (h *DedupHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+func (h *DedupHandler) Handle(ctx context.Context, r slog.Record) error {
+	nr := slog.NewRecord(r.Time, r.Level, r.Message, r.PC)
+	nr.AddAttrs(dedupAttrs(nestAttrs(h.goas, recordAttrs(r)), h.keepLast)...)
+	return h.next.Handle(ctx, nr)
+}
+
+func (h *DedupHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	if len(attrs) == 0 {
+		return h
+	}
+	return &DedupHandler{next: h.next, keepLast: h.keepLast, goas: appendGroupOrAttrs(h.goas, groupOrAttrs{attrs: attrs})}
+}
+
+func (h *DedupHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	return &DedupHandler{next: h.next, keepLast: h.keepLast, goas: appendGroupOrAttrs(h.goas, groupOrAttrs{group: name})}
+}
+
+// collapse duplicate keys, recursively in groups.
+// Values are resolved and inline groups (with empty key) are flattened
+func dedupAttrs(attrs []slog.Attr, keepLast bool) []slog.Attr {
+	flat := flattenInline(nil, attrs)
+
+	index := make(map[string]int, len(flat))
+	out := make([]slog.Attr, 0, len(flat))
+	for _, a := range flat {
+		i, exists := index[a.Key]
+		if !exists {
+			index[a.Key] = len(out)
+			out = append(out, a)
+			continue
+		}
+		if out[i].Value.Kind() == slog.KindGroup && a.Value.Kind() == slog.KindGroup {
+			merged := append(append([]slog.Attr{}, out[i].Value.Group()...), a.Value.Group()...)
+			out[i].Value = slog.GroupValue(merged...)
+		} else if keepLast {
+			out[i] = a
+		}
+	}
+
+	for i, a := range out {
+		if a.Value.Kind() == slog.KindGroup {
+			out[i].Value = slog.GroupValue(dedupAttrs(a.Value.Group(), keepLast)...)
+		}
+	}
+	return out
+}
+
+// append attrs to dst, resolved and with inline groups flattened
+func flattenInline(dst, attrs []slog.Attr) []slog.Attr {
+	for _, a := range attrs {
+		a.Value = a.Value.Resolve()
+		if a.Equal(slog.Attr{}) {
+			continue
+		}
+		if a.Key == "" && a.Value.Kind() == slog.KindGroup {
+			dst = flattenInline(dst, a.Value.Group())
+			continue
+		}
+		dst = append(dst, a)
+	}
+	return dst
+}
@@ -0,0 +1,47 @@
+package slogging
+
+import (
+	"bytes"
+	"log/slog"
+	"testing"
+)
+
+func TestDedupAttrs(t *testing.T) {
+	for _, tc := range []struct {
+		name        string
+		log         func(log *slog.Logger)
+		first, last string
+	}{
+		{"WithAttrs chain",
+			func(log *slog.Logger) { log.With("a", 1).With("a", 2).Info("m", "a", 3) },
+			`"a":1`, `"a":3`},
+		{"nested groups",
+			func(log *slog.Logger) {
+				log.Info("m", slog.Group("g", "b", 1, slog.Group("h", "c", 1)), slog.Group("g", "b", 2, slog.Group("h", "c", 2, "d", 2)))
+			},
+			`"g":{"b":1,"h":{"c":1,"d":2}}`, `"g":{"b":2,"h":{"c":2,"d":2}}`},
+		{"WithGroup chain",
+			func(log *slog.Logger) { log.With("a", 1).WithGroup("g").With("b", 1).Info("m", "b", 2, "a", 3) },
+			`"a":1,"g":{"b":1,"a":3}`, `"a":1,"g":{"b":2,"a":3}`},
+		{"inline group",
+			func(log *slog.Logger) { log.With("a", 1).Info("m", slog.Group("", "a", 2)) },
+			`"a":1`, `"a":2`},
+		{"group and value",
+			func(log *slog.Logger) { log.Info("m", "a", 1, slog.Group("a", "b", 2)) },
+			`"a":1`, `"a":{"b":2}`},
+	} {
+		for keep, expected := range map[string]string{DedupKeepFirst: tc.first, DedupKeepLast: tc.last} {
+			t.Run(tc.name+" "+keep, func(t *testing.T) {
+				var buf bytes.Buffer
+				log, _ := CreateWithOptions(slog.HandlerOptions{Level: slog.LevelInfo}, true,
+					WithWriter(&buf), WithoutTime(), WithDedupAttrs(keep))
+
+				tc.log(log)
+
+				if expected := `{"level":"INFO","msg":"m",` + expected + "}\n"; buf.String() != expected {
+					t.Errorf("expected %s, got %s", expected, buf.String())
+				}
+			})
+		}
+	}
+}
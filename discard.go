@@ -0,0 +1,21 @@
+package slogging
+
+import (
+	"context"
+	"log/slog"
+)
+
+// logger discarding everything. Its handler is never enabled, so the arguments
+// of log calls are not evaluated into records, e.g. for benchmarks
+func Discard() *slog.Logger {
+	return discardLogger
+}
+
+var discardLogger = slog.New(discardHandler{})
+
+type discardHandler struct{}
+
+func (discardHandler) Enabled(context.Context, slog.Level) bool  { return false }
+func (discardHandler) Handle(context.Context, slog.Record) error { return nil }
+func (h discardHandler) WithAttrs([]slog.Attr) slog.Handler      { return h }
+func (h discardHandler) WithGroup(string) slog.Handler           { return h }
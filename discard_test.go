@@ -0,0 +1,27 @@
+package slogging
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestDiscardNoAllocs(t *testing.T) {
+	log := Discard()
+	err := errors.New("failed")
+	allocs := testing.AllocsPerRun(100, func() {
+		log.Info("message", "n", 1, "err", err, "duration", time.Second)
+	})
+	if allocs != 0 {
+		t.Errorf("expected no allocations, got %v", allocs)
+	}
+}
+
+func BenchmarkDisabled(b *testing.B) {
+	log := Discard()
+	err := errors.New("failed")
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		log.Info("message", "n", 1, "err", err, "duration", time.Second)
+	}
+}
@@ -0,0 +1,34 @@
+package slogging
+
+import (
+	"log/slog"
+	"strconv"
+	"time"
+)
+
+// unit of durations from Duration and Since in JSON output.
+// Set during initialization, before logging
+var DurationUnit = time.Millisecond
+
+// attribute with the duration d. With JSON output it is a number in
+// DurationUnit (default milliseconds, e.g. 1500.5), so it can be graphed.
+// With text output it is in the readable form, e.g. 1.5005s
+func Duration(key string, d time.Duration) slog.Attr {
+	return slog.Any(key, durationValue(d))
+}
+
+// attribute with the duration since start, see Duration
+func Since(key string, start time.Time) slog.Attr {
+	return Duration(key, time.Since(start))
+}
+
+// marshals to a JSON number in DurationUnit, but prints as time.Duration
+type durationValue time.Duration
+
+func (d durationValue) String() string {
+	return time.Duration(d).String()
+}
+
+func (d durationValue) MarshalJSON() ([]byte, error) {
+	return strconv.AppendFloat(nil, float64(d)/float64(DurationUnit), 'f', -1, 64), nil
+}
@@ -0,0 +1,105 @@
+package slogging
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"strconv"
+)
+
+// environment variables read by CreateFromEnv
+const (
+	EnvLevel     = "LOG_LEVEL"
+	EnvFormat    = "LOG_FORMAT"
+	EnvAddSource = "LOG_ADD_SOURCE"
+	EnvOutput    = "LOG_OUTPUT"
+)
+
+// create logger (like Create) configured from environment variables:
+//
+//	LOG_LEVEL: level, e.g. debug, info, warn or error. Default info
+//	LOG_FORMAT: json or text. Default text
+//	LOG_ADD_SOURCE: bool (see strconv.ParseBool). Default false
+//	LOG_OUTPUT: stderr, stdout or a file path to append to. Default stderr
+//
+// A file output is registered with RegisterFlusher, so it is synced by Flush
+// and closed by Close
+func CreateFromEnv(attrs ...slog.Attr) (*slog.Logger, http.Handler, error) {
+	lvl, err := parseLevel(os.Getenv(EnvLevel))
+	if err != nil {
+		return nil, nil, fmt.Errorf("%s: %w", EnvLevel, err)
+	}
+	jsonOutput, err := parseFormat(os.Getenv(EnvFormat))
+	if err != nil {
+		return nil, nil, fmt.Errorf("%s: %w", EnvFormat, err)
+	}
+	addSource := false
+	if s := os.Getenv(EnvAddSource); s != "" {
+		addSource, err = strconv.ParseBool(s)
+		if err != nil {
+			return nil, nil, fmt.Errorf("%s: invalid bool %q", EnvAddSource, s)
+		}
+	}
+	w, err := openOutput(os.Getenv(EnvOutput))
+	if err != nil {
+		return nil, nil, fmt.Errorf("%s: %w", EnvOutput, err)
+	}
+
+	logger, h := CreateWithWriter(w, slog.HandlerOptions{Level: lvl, AddSource: addSource}, jsonOutput, attrs...)
+	return logger, h, nil
+}
+
+// parse level name (see parseLevelName). Empty is INFO
+func parseLevel(s string) (slog.Level, error) {
+	if s == "" {
+		return slog.LevelInfo, nil
+	}
+	return parseLevelName(s)
+}
+
+// parse format "json" or "text". Empty is text. Returns true for json
+func parseFormat(s string) (bool, error) {
+	switch s {
+	case "json":
+		return true, nil
+	case "text", "":
+		return false, nil
+	default:
+		return false, fmt.Errorf("invalid format %q, must be json or text", s)
+	}
+}
+
+// "stderr", "stdout" or a file path to append to. Empty is stderr.
+// A file is registered with RegisterFlusher, to be closed by Close
+func openOutput(s string) (io.Writer, error) {
+	switch s {
+	case "stderr", "":
+		return os.Stderr, nil
+	case "stdout":
+		return os.Stdout, nil
+	default:
+		f, err := os.OpenFile(s, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o644)
+		if err != nil {
+			return nil, fmt.Errorf("open output: %w", err)
+		}
+		out := outputFile{f}
+		RegisterFlusher(out)
+		return out, nil
+	}
+}
+
+// file opened by openOutput
+type outputFile struct {
+	*os.File
+}
+
+func (f outputFile) Flush() error {
+	return f.Sync()
+}
+
+func (f outputFile) Close() error {
+	DeregisterFlusher(f)
+	return f.File.Close()
+}
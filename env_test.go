@@ -0,0 +1,50 @@
+package slogging
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCreateFromEnvOutputFileClosed(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "out.log")
+	t.Setenv(EnvOutput, path)
+
+	log, _, err := CreateFromEnv()
+	if err != nil {
+		t.Fatal(err)
+	}
+	log.Info("before close")
+	if err := Close(); err != nil {
+		t.Fatal(err)
+	}
+	log.Info("after close")
+
+	b, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s := string(b); !strings.Contains(s, "before close") || strings.Contains(s, "after close") {
+		t.Errorf("expected only the record before close, got %q", s)
+	}
+	for _, f := range registeredFlushers() {
+		if out, ok := f.(outputFile); ok && out.Name() == path {
+			t.Error("expected the output file to be deregistered")
+		}
+	}
+}
+
+func TestOpenOutputFileWriteAfterClose(t *testing.T) {
+	w, err := openOutput(filepath.Join(t.TempDir(), "out.log"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := w.(outputFile).Close(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := w.Write([]byte("x\n")); !errors.Is(err, os.ErrClosed) {
+		t.Errorf("expected %v, got %v", os.ErrClosed, err)
+	}
+}
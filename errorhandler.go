@@ -0,0 +1,32 @@
+package slogging
+
+import (
+	"context"
+	"log/slog"
+)
+
+// calls onError with errors from the next handler, see WithErrorHandler
+type errorHandler struct {
+	next    slog.Handler
+	onError func(error)
+}
+
+func (h *errorHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+func (h *errorHandler) Handle(ctx context.Context, r slog.Record) error {
+	err := h.next.Handle(ctx, r)
+	if err != nil {
+		h.onError(err)
+	}
+	return err
+}
+
+func (h *errorHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &errorHandler{next: h.next.WithAttrs(attrs), onError: h.onError}
+}
+
+func (h *errorHandler) WithGroup(name string) slog.Handler {
+	return &errorHandler{next: h.next.WithGroup(name), onError: h.onError}
+}
@@ -0,0 +1,106 @@
+package slogging
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"reflect"
+	"runtime"
+)
+
+// key of the attribute from Err
+const ErrorKey = "error"
+
+// attribute "error" rendering err as a group with:
+//
+//	message: err.Error()
+//	chain: messages of the wrapped errors (see errors.Unwrap), if any
+//	stack: stack frames, if err (or a wrapped error) has a StackTrace method
+//
+// as e.g. github.com/pkg/errors. StackTrace may return []uintptr (program
+// counters) or a slice of values formatted with %+v.
+// With JSON output, the group is an object and chain and stack are arrays.
+// With text output, the keys are prefixed with "error.", e.g. error.message=...
+// Returns the empty Attr, which is omitted, for a nil err
+func Err(err error) slog.Attr {
+	if err == nil {
+		return slog.Attr{}
+	}
+	return slog.Attr{Key: ErrorKey, Value: errorValue(err)}
+}
+
+// ReplaceAttr func for slog.HandlerOptions rendering any attribute with an
+// error value as Err does, keeping the key
+func ExpandErrors(_ []string, a slog.Attr) slog.Attr {
+	if a.Value.Kind() != slog.KindAny {
+		return a
+	}
+	if err, ok := a.Value.Any().(error); ok && err != nil {
+		a.Value = errorValue(err)
+	}
+	return a
+}
+
+func errorValue(err error) slog.Value {
+	attrs := []slog.Attr{slog.String("message", err.Error())}
+	if chain := errorChain(err); len(chain) > 0 {
+		attrs = append(attrs, slog.Any("chain", chain))
+	}
+	if stack := errorStack(err); len(stack) > 0 {
+		attrs = append(attrs, slog.Any("stack", stack))
+	}
+	return slog.GroupValue(attrs...)
+}
+
+// messages of errors wrapped by err, depth-first
+func errorChain(err error) []string {
+	var chain []string
+	switch x := err.(type) {
+	case interface{ Unwrap() error }:
+		if inner := x.Unwrap(); inner != nil {
+			chain = append(chain, inner.Error())
+			chain = append(chain, errorChain(inner)...)
+		}
+	case interface{ Unwrap() []error }:
+		for _, inner := range x.Unwrap() {
+			if inner != nil {
+				chain = append(chain, inner.Error())
+				chain = append(chain, errorChain(inner)...)
+			}
+		}
+	}
+	return chain
+}
+
+// stack of the first error in the chain with a StackTrace method
+func errorStack(err error) []string {
+	var stack []string
+	for e := err; e != nil && stack == nil; e = errors.Unwrap(e) {
+		m := reflect.ValueOf(e).MethodByName("StackTrace")
+		if !m.IsValid() || m.Type().NumIn() != 0 || m.Type().NumOut() != 1 {
+			continue
+		}
+		out := m.Call(nil)[0]
+		if out.Kind() != reflect.Slice {
+			continue
+		}
+		if pcs, ok := out.Interface().([]uintptr); ok {
+			if len(pcs) == 0 {
+				continue
+			}
+			frames := runtime.CallersFrames(pcs)
+			for {
+				f, more := frames.Next()
+				stack = append(stack, fmt.Sprintf("%s %s:%d", f.Function, f.File, f.Line))
+				if !more {
+					break
+				}
+			}
+			continue
+		}
+		for i := 0; i < out.Len(); i++ {
+			stack = append(stack, fmt.Sprintf("%+v", out.Index(i).Interface()))
+		}
+	}
+	return stack
+}
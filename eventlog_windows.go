@@ -0,0 +1,50 @@
+//go:build windows
+
+package slogging
+
+import (
+	"io"
+	"log/slog"
+	"net/http"
+
+	"golang.org/x/sys/windows/svc/eventlog"
+)
+
+// event ID of records written by CreateEventLog
+const eventLogID = 1
+
+// create logger (like Create) writing records to the Windows Event Log with source.
+// Levels are mapped to event types: ERROR (and above) to Error, WARN to Warning
+// and INFO (and below) to Information.
+// The source must be registered, e.g. with InstallEventLogSource when installing the service.
+// Close the returned io.Closer on shutdown
+func CreateEventLog(source string, opts slog.HandlerOptions, jsonOutput bool, attrs ...slog.Attr) (*slog.Logger, http.Handler, io.Closer, error) {
+	el, err := eventlog.Open(source)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	logger, _, h := createLineSink(opts, jsonOutput, func(level slog.Level, line string) error {
+		switch {
+		case level >= slog.LevelError:
+			return el.Error(eventLogID, line)
+		case level >= slog.LevelWarn:
+			return el.Warning(eventLogID, line)
+		default:
+			return el.Info(eventLogID, line)
+		}
+	}, attrs)
+	return logger, h, el, nil
+}
+
+// register source in the registry for the Windows Event Log,
+// supporting Error, Warning and Information events.
+// Requires administrator privileges
+func InstallEventLogSource(source string) error {
+	return eventlog.InstallAsEventCreate(source, eventlog.Error|eventlog.Warning|eventlog.Info)
+}
+
+// remove source registered with InstallEventLogSource
+func RemoveEventLogSource(source string) error {
+	return eventlog.Remove(source)
+}
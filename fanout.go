@@ -0,0 +1,102 @@
+package slogging
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+)
+
+// FanoutHandler is a slog.Handler forwarding records to multiple handlers.
+// Use with slog.New(NewFanout(...))
+type FanoutHandler struct {
+	handlers []slog.Handler
+}
+
+// create handler forwarding to all of handlers
+func NewFanout(handlers ...slog.Handler) *FanoutHandler {
+	return &FanoutHandler{handlers: handlers}
+}
+
+// enabled if any of the handlers is enabled
+func (h *FanoutHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	for _, x := range h.handlers {
+		if x.Enabled(ctx, level) {
+			return true
+		}
+	}
+	return false
+}
+
+// forward to each enabled handler. All handlers are attempted and errors are joined
+func (h *FanoutHandler) Handle(ctx context.Context, r slog.Record) error {
+	var errs []error
+	for _, x := range h.handlers {
+		if !x.Enabled(ctx, r.Level) {
+			continue
+		}
+		if err := x.Handle(ctx, r.Clone()); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (h *FanoutHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	xs := make([]slog.Handler, len(h.handlers))
+	for i, x := range h.handlers {
+		xs[i] = x.WithAttrs(attrs)
+	}
+	return &FanoutHandler{handlers: xs}
+}
+
+func (h *FanoutHandler) WithGroup(name string) slog.Handler {
+	xs := make([]slog.Handler, len(h.handlers))
+	for i, x := range h.handlers {
+		xs[i] = x.WithGroup(name)
+	}
+	return &FanoutHandler{handlers: xs}
+}
+
+// returns a slog.Handler passing every record to primary and also records
+// at or above threshold to secondary, e.g. an alerting sink.
+// Attributes and groups apply to both
+func TeeAboveLevel(primary slog.Handler, threshold slog.Level, secondary slog.Handler) slog.Handler {
+	return &teeHandler{primary: primary, threshold: threshold, secondary: secondary}
+}
+
+type teeHandler struct {
+	primary   slog.Handler
+	threshold slog.Level
+	secondary slog.Handler
+}
+
+func (h *teeHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.primary.Enabled(ctx, level) ||
+		(level >= h.threshold && h.secondary.Enabled(ctx, level))
+}
+
+// pass to both handlers as applicable. Errors are joined
+func (h *teeHandler) Handle(ctx context.Context, r slog.Record) error {
+	var errs []error
+	if h.primary.Enabled(ctx, r.Level) {
+		errs = append(errs, h.primary.Handle(ctx, r.Clone()))
+	}
+	if r.Level >= h.threshold && h.secondary.Enabled(ctx, r.Level) {
+		errs = append(errs, h.secondary.Handle(ctx, r.Clone()))
+	}
+	return errors.Join(errs...)
+}
+
+func (h *teeHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &teeHandler{
+		primary:   h.primary.WithAttrs(attrs),
+		threshold: h.threshold,
+		secondary: h.secondary.WithAttrs(attrs)}
+}
+
+func (h *teeHandler) WithGroup(name string) slog.Handler {
+	return &teeHandler{
+		primary:   h.primary.WithGroup(name),
+		threshold: h.threshold,
+		secondary: h.secondary.WithGroup(name)}
+}
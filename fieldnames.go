@@ -0,0 +1,110 @@
+package slogging
+
+import (
+	"log/slog"
+)
+
+// preset of names for the built-in attributes, see FieldNames
+type FieldNamePreset int
+
+const (
+	// the slog names: "time", "level" and "msg"
+	FieldNamesSlog FieldNamePreset = iota
+	// Google Cloud Logging, see GCPFieldNames
+	FieldNamesGCP
+	// AWS CloudWatch Logs, see AWSFieldNames
+	FieldNamesAWS
+)
+
+// returns a ReplaceAttr func for slog.HandlerOptions applying preset.
+// nil for FieldNamesSlog and unknown presets
+func FieldNames(preset FieldNamePreset) func(groups []string, a slog.Attr) slog.Attr {
+	switch preset {
+	case FieldNamesGCP:
+		return GCPFieldNames()
+	case FieldNamesAWS:
+		return AWSFieldNames()
+	default:
+		return nil
+	}
+}
+
+// returns a ReplaceAttr func for slog.HandlerOptions, which renames the
+// built-in attributes for AWS CloudWatch Logs (e.g. from ECS): "time" to
+// "@timestamp" and "msg" to "message", keeping "level" as an upper-case name,
+// e.g. "WARN" or "FATAL" (see NamedLevels), which Logs Insights discovers as fields.
+// As it renames the built-in keys, ReplaceAttr funcs looking for them must
+// be applied before it
+func AWSFieldNames() func(groups []string, a slog.Attr) slog.Attr {
+	return ChainReplaceAttr(
+		renameBuiltins("@timestamp", "", "message"),
+		mapLevel(slog.LevelKey, levelName))
+}
+
+// returns a ReplaceAttr func for slog.HandlerOptions, which renames the
+// built-in attributes for Google Cloud Logging: "time" to "timestamp",
+// "level" to "severity" and "msg" to "message", with the level mapped to the
+// Cloud Logging severities DEBUG, INFO, WARNING, ERROR and CRITICAL (for
+// LevelFatal and above). Levels below DEBUG are DEBUG.
+// As it renames the built-in keys, ReplaceAttr funcs looking for them must
+// be applied before it
+func GCPFieldNames() func(groups []string, a slog.Attr) slog.Attr {
+	return ChainReplaceAttr(
+		renameBuiltins("timestamp", "severity", "message"),
+		mapLevel("severity", gcpSeverity))
+}
+
+func gcpSeverity(l slog.Level) string {
+	switch {
+	case l >= LevelFatal:
+		return "CRITICAL"
+	case l >= slog.LevelError:
+		return "ERROR"
+	case l >= slog.LevelWarn:
+		return "WARNING"
+	case l >= slog.LevelInfo:
+		return "INFO"
+	default:
+		return "DEBUG"
+	}
+}
+
+// ReplaceAttr func renaming the top-level built-in attributes. The time and
+// level are only renamed when of kind time and type slog.Level, so that
+// attributes named as them are mostly left alone. Empty keys are not renamed
+func renameBuiltins(timeKey, levelKey, msgKey string) func([]string, slog.Attr) slog.Attr {
+	return func(groups []string, a slog.Attr) slog.Attr {
+		if len(groups) > 0 {
+			return a
+		}
+		switch a.Key {
+		case slog.TimeKey:
+			if timeKey != "" && a.Value.Kind() == slog.KindTime {
+				a.Key = timeKey
+			}
+		case slog.LevelKey:
+			if _, ok := a.Value.Any().(slog.Level); ok && levelKey != "" {
+				a.Key = levelKey
+			}
+		case slog.MessageKey:
+			if msgKey != "" {
+				a.Key = msgKey
+			}
+		}
+		return a
+	}
+}
+
+// ReplaceAttr func rendering the top-level attribute key with name, when it is
+// a slog.Level
+func mapLevel(key string, name func(slog.Level) string) func([]string, slog.Attr) slog.Attr {
+	return func(groups []string, a slog.Attr) slog.Attr {
+		if len(groups) > 0 || a.Key != key {
+			return a
+		}
+		if lvl, ok := a.Value.Any().(slog.Level); ok {
+			a.Value = slog.StringValue(name(lvl))
+		}
+		return a
+	}
+}
@@ -0,0 +1,62 @@
+package slogging
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestFieldNames(t *testing.T) {
+	for _, tc := range []struct {
+		preset   FieldNamePreset
+		expected string
+	}{
+		{FieldNamesSlog, `"level":"WARN","msg":"m","a":1}`},
+		{FieldNamesAWS, `"level":"WARN","message":"m","a":1}`},
+		{FieldNamesGCP, `"severity":"WARNING","message":"m","a":1}`},
+	} {
+		var buf bytes.Buffer
+		log, _ := CreateWithOptions(slog.HandlerOptions{Level: slog.LevelInfo}, true,
+			WithWriter(&buf), WithFieldNames(tc.preset))
+		log.Warn("m", "a", 1)
+
+		if !strings.HasSuffix(buf.String(), tc.expected+"\n") {
+			t.Errorf("preset %d: expected %s, got %s", tc.preset, tc.expected, buf.String())
+		}
+		timeKey := map[FieldNamePreset]string{FieldNamesSlog: "time", FieldNamesAWS: "@timestamp", FieldNamesGCP: "timestamp"}[tc.preset]
+		if !strings.HasPrefix(buf.String(), `{"`+timeKey+`":`) {
+			t.Errorf("preset %d: expected time as %s, got %s", tc.preset, timeKey, buf.String())
+		}
+	}
+}
+
+func TestAWSFieldNamesChained(t *testing.T) {
+	// user func looking for the built-in message key, applied before the preset
+	user := func(groups []string, a slog.Attr) slog.Attr {
+		if len(groups) == 0 && a.Key == slog.MessageKey {
+			a.Value = slog.StringValue(strings.ToUpper(a.Value.String()))
+		}
+		if a.Key == "secret" {
+			return slog.Attr{}
+		}
+		return a
+	}
+	expected := `"level":"FATAL","message":"M","a":1}` + "\n"
+
+	var buf bytes.Buffer
+	opts := &slog.HandlerOptions{ReplaceAttr: ChainReplaceAttr(user, AWSFieldNames())}
+	slog.New(slog.NewJSONHandler(&buf, opts)).Log(context.Background(), LevelFatal, "m", "a", 1, "secret", "x")
+	if s := buf.String(); !strings.HasPrefix(s, `{"@timestamp":`) || !strings.HasSuffix(s, expected) {
+		t.Errorf("chained: expected %s, got %s", expected, s)
+	}
+
+	buf.Reset()
+	log, _ := CreateWithOptions(slog.HandlerOptions{Level: slog.LevelInfo, ReplaceAttr: user}, true,
+		WithWriter(&buf), WithAWSFieldNames())
+	log.Log(context.Background(), LevelFatal, "m", "a", 1, "secret", "x")
+	if s := buf.String(); !strings.HasPrefix(s, `{"@timestamp":`) || !strings.HasSuffix(s, expected) {
+		t.Errorf("option: expected %s, got %s", expected, s)
+	}
+}
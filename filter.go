@@ -0,0 +1,41 @@
+package slogging
+
+import (
+	"context"
+	"log/slog"
+)
+
+// FilterHandler is a slog.Handler passing only records for which keep returns
+// true to the next handler, e.g. to drop DEBUG records from a package (see
+// slog.Record.PC). Enabled is delegated to next, so keep is only called for
+// enabled records, but it is called for every one of those, so it must be cheap.
+// The record given to keep has the attributes of the log call, but not those
+// from WithAttrs (or With)
+type FilterHandler struct {
+	next slog.Handler
+	keep func(ctx context.Context, r slog.Record) bool
+}
+
+// create FilterHandler passing records for which keep returns true to next
+func NewFilterHandler(next slog.Handler, keep func(ctx context.Context, r slog.Record) bool) *FilterHandler {
+	return &FilterHandler{next: next, keep: keep}
+}
+
+func (h *FilterHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+func (h *FilterHandler) Handle(ctx context.Context, r slog.Record) error {
+	if !h.keep(ctx, r) {
+		return nil
+	}
+	return h.next.Handle(ctx, r)
+}
+
+func (h *FilterHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &FilterHandler{next: h.next.WithAttrs(attrs), keep: h.keep}
+}
+
+func (h *FilterHandler) WithGroup(name string) slog.Handler {
+	return &FilterHandler{next: h.next.WithGroup(name), keep: h.keep}
+}
@@ -0,0 +1,84 @@
+package slogging
+
+import (
+	"flag"
+	"fmt"
+	"log/slog"
+	"net/http"
+)
+
+// configuration from command-line flags, see RegisterFlags
+type FlagConfig struct {
+	Level      slog.Level
+	JSONOutput bool
+	AddSource  bool
+	Output     string
+}
+
+// register flags on fs (flag.CommandLine if nil), with the same values and
+// defaults as CreateFromEnv:
+//
+//	-log.level: level, e.g. debug, info, warn or error. Default info
+//	-log.format: json or text. Default text
+//	-log.source: add the source to each record. Default false
+//	-log.output: stderr, stdout or a file path to append to. Default stderr
+//
+// Invalid levels and formats are reported by fs.Parse.
+// Call FlagConfig.Build after parsing
+func RegisterFlags(fs *flag.FlagSet) *FlagConfig {
+	if fs == nil {
+		fs = flag.CommandLine
+	}
+	c := &FlagConfig{Level: slog.LevelInfo, Output: "stderr"}
+	fs.Var((*levelFlag)(&c.Level), "log.level", "log level: "+acceptedLevels)
+	fs.Var((*formatFlag)(&c.JSONOutput), "log.format", "log format: json or text")
+	fs.BoolVar(&c.AddSource, "log.source", false, "add source file and line to log records")
+	fs.StringVar(&c.Output, "log.output", c.Output, "log output: stderr, stdout or a file path to append to")
+	return c
+}
+
+// create logger (like Create) as configured, with attrs attached.
+// A file output is registered with RegisterFlusher, to be closed by Close
+func (c *FlagConfig) Build(attrs ...slog.Attr) (*slog.Logger, http.Handler, error) {
+	w, err := openOutput(c.Output)
+	if err != nil {
+		return nil, nil, fmt.Errorf("log.output: %w", err)
+	}
+	logger, h := CreateWithWriter(w, slog.HandlerOptions{Level: c.Level, AddSource: c.AddSource}, c.JSONOutput, attrs...)
+	return logger, h, nil
+}
+
+// flag.Value for a level, parsed as by parseLevel
+type levelFlag slog.Level
+
+func (f *levelFlag) String() string {
+	return slog.Level(*f).String()
+}
+
+func (f *levelFlag) Set(s string) error {
+	lvl, err := parseLevel(s)
+	if err != nil {
+		return err
+	}
+	*f = levelFlag(lvl)
+	return nil
+}
+
+// flag.Value for a format, parsed as by parseFormat. true for json
+type formatFlag bool
+
+func (f *formatFlag) String() string {
+	if f != nil && *f {
+		return "json"
+	}
+	return "text"
+}
+
+func (f *formatFlag) Set(s string) error {
+	jsonOutput, err := parseFormat(s)
+	if err != nil {
+		return err
+	}
+	*f = formatFlag(jsonOutput)
+	return nil
+}
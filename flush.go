@@ -0,0 +1,75 @@
+package slogging
+
+import (
+	"errors"
+	"io"
+	"sync"
+)
+
+// implemented by handlers and writers buffering records
+type Flusher interface {
+	Flush() error
+}
+
+var (
+	flushersMu sync.Mutex
+	flushers   = make(map[Flusher]struct{})
+)
+
+// register f to be flushed by Flush, and closed by Close if it is an io.Closer.
+// Handlers and writers in this package which buffer are registered when
+// created and deregistered when closed
+func RegisterFlusher(f Flusher) {
+	flushersMu.Lock()
+	defer flushersMu.Unlock()
+	flushers[f] = struct{}{}
+}
+
+// remove f registered with RegisterFlusher
+func DeregisterFlusher(f Flusher) {
+	flushersMu.Lock()
+	defer flushersMu.Unlock()
+	delete(flushers, f)
+}
+
+// flush all registered handlers and writers, e.g. deferred in main.
+// Called by Fatal before exiting. Errors are joined
+func Flush() error {
+	var errs []error
+	for _, f := range registeredFlushers() {
+		if err := f.Flush(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// flush and close all registered handlers and writers, e.g. deferred in main
+// on shutdown, stopping their background goroutines. Those which can not be
+// closed are flushed. Records logged to the closed handlers and writers are
+// rejected. Errors are joined
+func Close() error {
+	var errs []error
+	for _, f := range registeredFlushers() {
+		var err error
+		if c, ok := f.(io.Closer); ok {
+			err = c.Close()
+		} else {
+			err = f.Flush()
+		}
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func registeredFlushers() []Flusher {
+	flushersMu.Lock()
+	defer flushersMu.Unlock()
+	xs := make([]Flusher, 0, len(flushers))
+	for f := range flushers {
+		xs = append(xs, f)
+	}
+	return xs
+}
@@ -0,0 +1,55 @@
+package slogging
+
+import (
+	"context"
+	"log/slog"
+	"sync/atomic"
+)
+
+// formatSwitch is a slog.Handler passing records to either a JSON or text
+// handler, as selected by useJSON, which may change at any time.
+// Attributes and groups are applied to both
+type formatSwitch struct {
+	useJSON  *atomic.Bool
+	jsonNext slog.Handler
+	textNext slog.Handler
+}
+
+func newFormatSwitch(useJSON *atomic.Bool, jsonNext, textNext slog.Handler) *formatSwitch {
+	return &formatSwitch{useJSON: useJSON, jsonNext: jsonNext, textNext: textNext}
+}
+
+func (h *formatSwitch) active() slog.Handler {
+	if h.useJSON.Load() {
+		return h.jsonNext
+	}
+	return h.textNext
+}
+
+func (h *formatSwitch) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.active().Enabled(ctx, level)
+}
+
+func (h *formatSwitch) Handle(ctx context.Context, r slog.Record) error {
+	return h.active().Handle(ctx, r)
+}
+
+func (h *formatSwitch) WithAttrs(attrs []slog.Attr) slog.Handler {
+	if len(attrs) == 0 {
+		return h
+	}
+	return &formatSwitch{
+		useJSON:  h.useJSON,
+		jsonNext: h.jsonNext.WithAttrs(attrs),
+		textNext: h.textNext.WithAttrs(attrs)}
+}
+
+func (h *formatSwitch) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	return &formatSwitch{
+		useJSON:  h.useJSON,
+		jsonNext: h.jsonNext.WithGroup(name),
+		textNext: h.textNext.WithGroup(name)}
+}
@@ -1,3 +1,5 @@
 module github.com/bredtape/slogging
 
 go 1.21.0
+
+require golang.org/x/sys v0.20.0
@@ -0,0 +1,97 @@
+package slogging
+
+import (
+	"context"
+	"log/slog"
+)
+
+// key of the marker attribute added by WithMaxGroupDepth when groups are flattened
+const GroupsFlattenedKey = "groups_flattened"
+
+// nests groups at most max deep, see WithMaxGroupDepth
+type groupDepthHandler struct {
+	next slog.Handler
+	max  int
+	// groups opened on next, dotted key prefix of the groups beyond max
+	// and whether attributes were flattened with WithAttrs
+	depth     int
+	prefix    string
+	flattened bool
+}
+
+func (h *groupDepthHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+func (h *groupDepthHandler) Handle(ctx context.Context, r slog.Record) error {
+	attrs, flattened := limitGroupDepth(recordAttrs(r), h.depth, h.max, h.prefix)
+	if !flattened && !h.flattened {
+		return h.next.Handle(ctx, r)
+	}
+
+	nr := slog.NewRecord(r.Time, r.Level, r.Message, r.PC)
+	nr.AddAttrs(attrs...)
+	nr.AddAttrs(slog.Bool(GroupsFlattenedKey, true))
+	return h.next.Handle(ctx, nr)
+}
+
+func (h *groupDepthHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	if len(attrs) == 0 {
+		return h
+	}
+	kept, flattened := limitGroupDepth(attrs, h.depth, h.max, h.prefix)
+	h2 := *h
+	h2.next = h.next.WithAttrs(kept)
+	h2.flattened = h.flattened || flattened
+	return &h2
+}
+
+func (h *groupDepthHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	h2 := *h
+	if h.prefix == "" && h.depth < h.max {
+		h2.next = h.next.WithGroup(name)
+		h2.depth++
+	} else {
+		h2.prefix += name + "."
+	}
+	return &h2
+}
+
+// attrs with groups nested deeper than max flattened to attributes with
+// dotted keys (e.g. "a.b.c"), given the groups already opened (depth) and the
+// key prefix of the flattened groups. Reports whether any attribute was flattened
+func limitGroupDepth(attrs []slog.Attr, depth, max int, prefix string) ([]slog.Attr, bool) {
+	kept := make([]slog.Attr, 0, len(attrs))
+	flattened := false
+	for _, a := range attrs {
+		a.Value = a.Value.Resolve()
+		if a.Value.Kind() != slog.KindGroup {
+			if prefix != "" {
+				a.Key = prefix + a.Key
+				flattened = true
+			}
+			kept = append(kept, a)
+			continue
+		}
+
+		var group []slog.Attr
+		var f bool
+		switch {
+		case a.Key == "":
+			// inlined
+			group, f = limitGroupDepth(a.Value.Group(), depth, max, prefix)
+			kept = append(kept, group...)
+		case prefix == "" && depth < max:
+			group, f = limitGroupDepth(a.Value.Group(), depth+1, max, "")
+			kept = append(kept, slog.Attr{Key: a.Key, Value: slog.GroupValue(group...)})
+		default:
+			group, f = limitGroupDepth(a.Value.Group(), depth+1, max, prefix+a.Key+".")
+			kept = append(kept, group...)
+		}
+		flattened = flattened || f
+	}
+	return kept, flattened
+}
@@ -0,0 +1,72 @@
+package slogging
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+// depth of nested objects in the JSON object v
+func jsonDepth(v any) int {
+	m, ok := v.(map[string]any)
+	if !ok {
+		return 0
+	}
+	depth := 0
+	for _, x := range m {
+		depth = max(depth, jsonDepth(x))
+	}
+	return depth + 1
+}
+
+func TestMaxGroupDepth(t *testing.T) {
+	var buf bytes.Buffer
+	log, _ := CreateWithOptions(slog.HandlerOptions{Level: slog.LevelInfo}, true,
+		WithWriter(&buf), WithMaxGroupDepth(3))
+
+	l := log
+	for i := 0; i < 100; i++ {
+		l = l.WithGroup(fmt.Sprintf("g%d", i))
+	}
+	l.Info("deep", "a", 1)
+
+	var rec map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &rec); err != nil {
+		t.Fatalf("invalid JSON %q: %v", buf.Bytes(), err)
+	}
+	// the record object itself and 3 groups
+	if depth := jsonDepth(rec); depth != 4 {
+		t.Errorf("expected depth 4, got %d in %s", depth, buf.Bytes())
+	}
+
+	g2 := rec["g0"].(map[string]any)["g1"].(map[string]any)["g2"].(map[string]any)
+	var key strings.Builder
+	for i := 3; i < 100; i++ {
+		fmt.Fprintf(&key, "g%d.", i)
+	}
+	key.WriteString("a")
+	if v, ok := g2[key.String()]; !ok || v != 1.0 {
+		t.Errorf("expected flattened attribute %s=1, got %v", key.String(), g2)
+	}
+	if g2[GroupsFlattenedKey] != true {
+		t.Errorf("expected marker %s, got %v", GroupsFlattenedKey, g2)
+	}
+}
+
+func TestMaxGroupDepthAttrs(t *testing.T) {
+	var buf bytes.Buffer
+	log, _ := CreateWithOptions(slog.HandlerOptions{Level: slog.LevelInfo}, true,
+		WithWriter(&buf), WithoutTime(), WithMaxGroupDepth(2))
+
+	log.WithGroup("a").Info("m", slog.Group("b", slog.Group("c", "d", 1)), slog.Group("e", "f", 2))
+	log.Info("shallow", slog.Group("b", "c", 1))
+
+	expected := `{"level":"INFO","msg":"m","a":{"b":{"c.d":1},"e":{"f":2},"groups_flattened":true}}` + "\n" +
+		`{"level":"INFO","msg":"shallow","b":{"c":1}}` + "\n"
+	if buf.String() != expected {
+		t.Errorf("expected:\n%s\ngot:\n%s", expected, buf.String())
+	}
+}
@@ -0,0 +1,29 @@
+package slogging
+
+import (
+	"log/slog"
+	"os"
+	"sync"
+)
+
+// attribute keys of WithHostInfo
+const (
+	HostKey = "host"
+	PIDKey  = "pid"
+)
+
+// looked up once, as it does not change. "unknown" if the lookup fails
+var hostname = sync.OnceValue(func() string {
+	name, err := os.Hostname()
+	if err != nil || name == "" {
+		return "unknown"
+	}
+	return name
+})
+
+// attach the attributes HostKey with the hostname (or "unknown" if it can
+// not be found) and PIDKey with the process ID to every record, e.g. to tell
+// replicas apart. They are added with those of WithAttrs, in option order
+func WithHostInfo() Option {
+	return WithAttrs(slog.String(HostKey, hostname()), slog.Int(PIDKey, os.Getpid()))
+}
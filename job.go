@@ -0,0 +1,44 @@
+package slogging
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+	"log/slog"
+	"net/http"
+)
+
+// attribute keys of CreateJobLogger
+const (
+	JobKey   = "job"
+	RunIDKey = "runID"
+)
+
+// create logger (like Create) for a background job run, e.g. from cron or
+// a worker, with the attributes JobKey=jobName and RunIDKey set to a random
+// UUID for the run, and a ContextHandler (see WithContextHandler), so the
+// attributes of the context of each record apply, e.g. from ContextWithAttrs,
+// so log with the *Context methods and the job's ctx (or contexts derived from it).
+// When ctx is done, e.g. the job is canceled or times out, it is logged at DEBUG
+// with the cause
+func CreateJobLogger(ctx context.Context, jobName string, opts slog.HandlerOptions, jsonOutput bool) (*slog.Logger, http.Handler) {
+	logger, h := CreateWithOptions(opts, jsonOutput,
+		WithContextHandler(),
+		WithAttrs(
+			slog.String(JobKey, jobName),
+			slog.String(RunIDKey, newUUID())))
+	context.AfterFunc(ctx, func() {
+		logger.LogAttrs(ctx, slog.LevelDebug, "job context done", slog.String("cause", context.Cause(ctx).Error()))
+	})
+	return logger, h
+}
+
+// random (version 4) UUID
+func newUUID() string {
+	var b [16]byte
+	// never returns an error, see crypto/rand.Read
+	_, _ = rand.Read(b[:])
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+}
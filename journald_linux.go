@@ -0,0 +1,186 @@
+//go:build linux
+
+package slogging
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// socket of the journald native protocol. Replaced in tests
+var journaldSocket = "/run/systemd/journal/socket"
+
+// create logger (like Create) sending records to systemd-journald with its
+// native protocol, keeping the attributes as journal fields: the message as
+// MESSAGE, the level as PRIORITY (FATAL 2, ERROR 3, WARN 4, INFO 6 and DEBUG
+// and below 7), the source (with AddSource) as CODE_FILE, CODE_LINE and
+// CODE_FUNC, and attributes with upper-cased keys, e.g. "user.id" in group
+// "req" as REQ_USER_ID. Characters not allowed in field names become '_'.
+// opts.ReplaceAttr is applied to the attributes, but not to the built-in fields.
+// Records larger than a datagram are rejected by the socket, returning an
+// error from the handler.
+// Returns an error if journald is not running. On other platforms than
+// Linux, text records are written to os.Stderr.
+// Close the returned io.Closer on shutdown
+func CreateJournald(opts slog.HandlerOptions, attrs ...slog.Attr) (*slog.Logger, http.Handler, io.Closer, error) {
+	conn, err := net.DialUnix("unixgram", nil, &net.UnixAddr{Name: journaldSocket, Net: "unixgram"})
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	h := &journaldHandler{
+		conn:       conn,
+		opts:       opts,
+		identifier: filepath.Base(os.Args[0])}
+	var level slog.Leveler = slog.LevelInfo
+	if opts.Level != nil {
+		level = opts.Level
+	}
+	logger, lh := CreateWithHandler(h, level, attrs...)
+	return logger, lh, conn, nil
+}
+
+// writes records to journald. Levels are filtered by the levelFilter of CreateWithHandler
+type journaldHandler struct {
+	conn       *net.UnixConn
+	opts       slog.HandlerOptions
+	identifier string
+
+	// fields from WithAttrs
+	preformatted []byte
+	groups       []string
+}
+
+func (h *journaldHandler) Enabled(context.Context, slog.Level) bool {
+	return true
+}
+
+func (h *journaldHandler) Handle(_ context.Context, r slog.Record) error {
+	var buf bytes.Buffer
+	appendJournalField(&buf, "MESSAGE", r.Message)
+	appendJournalField(&buf, "PRIORITY", strconv.Itoa(journaldPriority(r.Level)))
+	appendJournalField(&buf, "SYSLOG_IDENTIFIER", h.identifier)
+	if h.opts.AddSource && r.PC != 0 {
+		fs := runtime.CallersFrames([]uintptr{r.PC})
+		f, _ := fs.Next()
+		appendJournalField(&buf, "CODE_FILE", f.File)
+		appendJournalField(&buf, "CODE_LINE", strconv.Itoa(f.Line))
+		appendJournalField(&buf, "CODE_FUNC", f.Function)
+	}
+	buf.Write(h.preformatted)
+	r.Attrs(func(a slog.Attr) bool {
+		h.appendAttr(&buf, h.groups, a)
+		return true
+	})
+
+	_, err := h.conn.Write(buf.Bytes())
+	return err
+}
+
+func (h *journaldHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	if len(attrs) == 0 {
+		return h
+	}
+	h2 := *h
+	var buf bytes.Buffer
+	buf.Write(h.preformatted)
+	for _, a := range attrs {
+		h.appendAttr(&buf, h.groups, a)
+	}
+	h2.preformatted = buf.Bytes()
+	return &h2
+}
+
+func (h *journaldHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	h2 := *h
+	h2.groups = append(h.groups[:len(h.groups):len(h.groups)], name)
+	return &h2
+}
+
+func (h *journaldHandler) appendAttr(buf *bytes.Buffer, groups []string, a slog.Attr) {
+	a.Value = a.Value.Resolve()
+	if a.Value.Kind() == slog.KindGroup {
+		if a.Key != "" {
+			groups = append(groups[:len(groups):len(groups)], a.Key)
+		}
+		for _, x := range a.Value.Group() {
+			h.appendAttr(buf, groups, x)
+		}
+		return
+	}
+	if h.opts.ReplaceAttr != nil {
+		a = h.opts.ReplaceAttr(groups, a)
+		if a.Equal(slog.Attr{}) {
+			return
+		}
+		a.Value = a.Value.Resolve()
+	}
+	name := journalFieldName(append(groups[:len(groups):len(groups)], a.Key))
+	if name == "" {
+		return
+	}
+	appendJournalField(buf, name, a.Value.String())
+}
+
+// field name from the group and attribute keys: upper-cased, joined with '_',
+// only A-Z, 0-9 and '_', not starting with '_' or a digit and at most 64 characters.
+// Empty if nothing remains
+func journalFieldName(keys []string) string {
+	s := strings.Map(func(r rune) rune {
+		r = unicode.ToUpper(r)
+		if (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') {
+			return r
+		}
+		return '_'
+	}, strings.Join(keys, "_"))
+	s = strings.TrimLeft(s, "_0123456789")
+	if len(s) > 64 {
+		s = s[:64]
+	}
+	return s
+}
+
+// append a field in the native protocol. Values with a newline are
+// length-prefixed
+func appendJournalField(buf *bytes.Buffer, name, value string) {
+	if !strings.Contains(value, "\n") {
+		fmt.Fprintf(buf, "%s=%s\n", name, value)
+		return
+	}
+	buf.WriteString(name)
+	buf.WriteByte('\n')
+	_ = binary.Write(buf, binary.LittleEndian, uint64(len(value)))
+	buf.WriteString(value)
+	buf.WriteByte('\n')
+}
+
+// syslog priority of level
+func journaldPriority(level slog.Level) int {
+	switch {
+	case level >= LevelFatal:
+		return 2
+	case level >= slog.LevelError:
+		return 3
+	case level >= slog.LevelWarn:
+		return 4
+	case level >= slog.LevelInfo:
+		return 6
+	default:
+		return 7
+	}
+}
@@ -0,0 +1,93 @@
+//go:build linux
+
+package slogging
+
+import (
+	"bytes"
+	"encoding/binary"
+	"log/slog"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// listen on a socket replacing journaldSocket until the test ends
+func fakeJournald(t *testing.T) *net.UnixConn {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "journal.socket")
+	conn, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Name: path, Net: "unixgram"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	prev := journaldSocket
+	t.Cleanup(func() { journaldSocket = prev })
+	journaldSocket = path
+	return conn
+}
+
+func readJournalDatagram(t *testing.T, conn *net.UnixConn) []byte {
+	t.Helper()
+	_ = conn.SetReadDeadline(time.Now().Add(time.Second))
+	buf := make([]byte, 64*1024)
+	n, err := conn.Read(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return buf[:n]
+}
+
+func TestCreateJournald(t *testing.T) {
+	conn := fakeJournald(t)
+	log, _, closer, err := CreateJournald(slog.HandlerOptions{Level: slog.LevelInfo}, slog.String("app", "test"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer closer.Close()
+
+	log.Debug("dropped")
+	log.WithGroup("req").Warn("slow", "user.id", 42, "query", "select 1\nfrom t")
+
+	var multiline bytes.Buffer
+	multiline.WriteString("REQ_QUERY\n")
+	_ = binary.Write(&multiline, binary.LittleEndian, uint64(len("select 1\nfrom t")))
+	multiline.WriteString("select 1\nfrom t\n")
+	expected := "MESSAGE=slow\n" +
+		"PRIORITY=4\n" +
+		"SYSLOG_IDENTIFIER=" + filepath.Base(os.Args[0]) + "\n" +
+		"APP=test\n" +
+		"REQ_USER_ID=42\n" +
+		multiline.String()
+	if got := readJournalDatagram(t, conn); string(got) != expected {
+		t.Errorf("expected:\n%q\ngot:\n%q", expected, got)
+	}
+}
+
+func TestCreateJournaldNotRunning(t *testing.T) {
+	prev := journaldSocket
+	defer func() { journaldSocket = prev }()
+	journaldSocket = filepath.Join(t.TempDir(), "missing.socket")
+
+	if _, _, _, err := CreateJournald(slog.HandlerOptions{}); err == nil {
+		t.Error("expected error without journald")
+	}
+}
+
+func TestJournaldPriority(t *testing.T) {
+	for level, expected := range map[slog.Level]int{
+		LevelFatal:          2,
+		slog.LevelError + 1: 3,
+		slog.LevelError:     3,
+		slog.LevelWarn:      4,
+		slog.LevelInfo:      6,
+		slog.LevelDebug:     7,
+		LevelTrace:          7,
+	} {
+		if p := journaldPriority(level); p != expected {
+			t.Errorf("expected priority %d for %s, got %d", expected, level, p)
+		}
+	}
+}
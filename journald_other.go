@@ -0,0 +1,23 @@
+//go:build !linux
+
+package slogging
+
+import (
+	"io"
+	"log/slog"
+	"net/http"
+)
+
+// create logger (like Create) with text output to os.Stderr, as journald is
+// only supported on Linux. See the Linux version.
+// The returned io.Closer does nothing
+func CreateJournald(opts slog.HandlerOptions, attrs ...slog.Attr) (*slog.Logger, http.Handler, io.Closer, error) {
+	logger, h := Create(opts, false, attrs...)
+	return logger, h, nopCloser{}, nil
+}
+
+type nopCloser struct{}
+
+func (nopCloser) Close() error {
+	return nil
+}
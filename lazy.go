@@ -0,0 +1,49 @@
+package slogging
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+)
+
+// lazy helpers only call args when the level is enabled. This is the
+// recommended way to log attributes which are costly to construct on hot paths.
+
+// log msg at level with the attributes (as for slog.Logger.Log) returned by args,
+// which is only called if the level is enabled
+func LogLazy(log *slog.Logger, level slog.Level, msg string, args func() []any) {
+	logLazy(context.Background(), log, level, msg, args)
+}
+
+// like LogLazy with ctx
+func LogLazyContext(ctx context.Context, log *slog.Logger, level slog.Level, msg string, args func() []any) {
+	logLazy(ctx, log, level, msg, args)
+}
+
+// like Debugf, but args is only called if DEBUG is enabled
+func DebugfLazy(log *slog.Logger, format string, args func() []any) {
+	logfLazy(context.Background(), log, slog.LevelDebug, format, args)
+}
+
+// like Infof, but args is only called if INFO is enabled
+func InfofLazy(log *slog.Logger, format string, args func() []any) {
+	logfLazy(context.Background(), log, slog.LevelInfo, format, args)
+}
+
+// must be called directly by the exported helper
+func logLazy(ctx context.Context, log *slog.Logger, level slog.Level, msg string, args func() []any) {
+	if !log.Enabled(ctx, level) {
+		return
+	}
+	// skip runtime.Callers, logSkip, logLazy and the helper
+	logSkip(ctx, log, level, 4, msg, args()...)
+}
+
+// must be called directly by the exported helper
+func logfLazy(ctx context.Context, log *slog.Logger, level slog.Level, format string, args func() []any) {
+	if !log.Enabled(ctx, level) {
+		return
+	}
+	// skip runtime.Callers, logSkip, logfLazy and the helper
+	logSkip(ctx, log, level, 4, fmt.Sprintf(format, args()...))
+}
@@ -0,0 +1,70 @@
+package slogging
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// minimum interval between the audit records of level changes by a level
+// handler. Changes within the interval are coalesced into one record, written
+// at the end of it
+var levelAuditInterval = time.Second
+
+// writes audit records of the changes by a level handler to its logger,
+// at most one per levelAuditInterval
+type levelAudit struct {
+	// the logger of the level handler. slog.Default() if nil
+	log *slog.Logger
+
+	mu   sync.Mutex
+	last time.Time
+	// latest change not yet written, and the number of changes it replaces
+	pending   *slog.Record
+	coalesced int
+}
+
+// write an INFO record with msg and attrs, or coalesce it with the following
+// changes if a record was written within levelAuditInterval
+func (a *levelAudit) record(msg string, attrs ...slog.Attr) {
+	r := slog.NewRecord(time.Now(), slog.LevelInfo, msg, 0)
+	r.AddAttrs(attrs...)
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.pending == nil && r.Time.Sub(a.last) >= levelAuditInterval {
+		a.last = r.Time
+		a.write(r)
+		return
+	}
+	if a.pending == nil {
+		time.AfterFunc(levelAuditInterval-r.Time.Sub(a.last), a.flush)
+	}
+	a.pending = &r
+	a.coalesced++
+}
+
+// write the pending record, with the number of changes coalesced into it
+func (a *levelAudit) flush() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.pending == nil {
+		return
+	}
+	r := *a.pending
+	r.AddAttrs(slog.Int("coalesced", a.coalesced))
+	a.pending, a.coalesced = nil, 0
+	a.last = time.Now()
+	a.write(r)
+}
+
+// must be called with mu held, so records are written in order.
+// Written regardless of the level, so that raising the level is also audited
+func (a *levelAudit) write(r slog.Record) {
+	log := a.log
+	if log == nil {
+		log = slog.Default()
+	}
+	_ = log.Handler().Handle(context.Background(), r)
+}
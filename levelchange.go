@@ -0,0 +1,42 @@
+package slogging
+
+import (
+	"fmt"
+	"log/slog"
+	"sync"
+)
+
+var (
+	levelCallbacksMu sync.RWMutex
+	levelCallbacks   []func(old, new slog.Level)
+)
+
+// register f to be called when a level is changed, by the http.Handler of this
+// package (including reset), InstallSignalLevelControl or WatchLevelFile.
+// It is only called when the level actually changes.
+// Callbacks run in their own goroutine, so they do not delay the change
+// (e.g. the HTTP response), and a panic in f is recovered and logged
+func RegisterLevelChangeCallback(f func(old, new slog.Level)) {
+	levelCallbacksMu.Lock()
+	defer levelCallbacksMu.Unlock()
+	levelCallbacks = append(levelCallbacks, f)
+}
+
+// call the registered callbacks, if the level changed
+func notifyLevelChange(old, new slog.Level) {
+	if old == new {
+		return
+	}
+	levelCallbacksMu.RLock()
+	defer levelCallbacksMu.RUnlock()
+	for _, f := range levelCallbacks {
+		go func(f func(old, new slog.Level)) {
+			defer func() {
+				if v := recover(); v != nil {
+					slog.Error("level change callback panicked", "panic", fmt.Sprint(v))
+				}
+			}()
+			f(old, new)
+		}(f)
+	}
+}
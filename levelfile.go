@@ -0,0 +1,95 @@
+package slogging
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io/fs"
+	"log/slog"
+	"os"
+	"sync"
+	"time"
+)
+
+// how often WatchLevelFile reads the file
+var levelFilePollInterval = 2 * time.Second
+
+// set the level of v from the file at path, e.g. a mounted Kubernetes ConfigMap
+// key, and keep following it as the file changes.
+// The file holds a level as accepted by the http.Handler, e.g. "DEBUG" or "-4",
+// surrounded by optional whitespace.
+// The file is polled, which also works for the symlink swap done when a
+// ConfigMap is updated. Each change is logged with the default logger, as
+// are invalid contents, which leave the level unchanged.
+// If the file is absent (at start or later), the level is left unchanged.
+// Call stop to stop watching
+func WatchLevelFile(path string, v *slog.LevelVar) (stop func()) {
+	w := &levelFileWatcher{path: path, v: v}
+	w.poll()
+
+	done := make(chan struct{})
+	go func() {
+		t := time.NewTicker(levelFilePollInterval)
+		defer t.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-t.C:
+				w.poll()
+			}
+		}
+	}()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() { close(done) })
+	}
+}
+
+type levelFileWatcher struct {
+	path string
+	v    *slog.LevelVar
+	// contents last read, to only act on changes
+	last []byte
+	read bool
+	// whether the last read failed, to only report it once
+	failed bool
+}
+
+func (w *levelFileWatcher) poll() {
+	b, err := os.ReadFile(w.path)
+	if err != nil {
+		if !errors.Is(err, fs.ErrNotExist) && !w.failed {
+			slog.Error("failed to read log level file", "path", w.path, "err", err)
+		}
+		w.failed = true
+		// act again if the file reappears with the same contents
+		w.read = false
+		return
+	}
+	w.failed = false
+	b = bytes.TrimSpace(b)
+	if w.read && bytes.Equal(b, w.last) {
+		return
+	}
+	w.last, w.read = b, true
+
+	lvl, err := parseLevelName(string(b))
+	if err != nil {
+		slog.Error("invalid log level file", "path", w.path, "err", err)
+		return
+	}
+	levelChangeMu.Lock()
+	prev := w.v.Level()
+	w.v.Set(lvl)
+	levelChangeMu.Unlock()
+	if lvl == prev {
+		return
+	}
+	slog.LogAttrs(context.Background(), slog.LevelInfo, "log level set",
+		slog.String("newLevel", levelName(lvl)),
+		slog.String("previousLevel", levelName(prev)),
+		slog.String("path", w.path))
+	notifyLevelChange(prev, lvl)
+}
@@ -0,0 +1,57 @@
+package slogging
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+)
+
+// create logger (like Create) with the handler h, e.g. from another package.
+// Records below the level are filtered before reaching h, and the level
+// can be changed with the returned http.Handler
+func CreateWithHandler(h slog.Handler, level slog.Leveler, attrs ...slog.Attr) (*slog.Logger, http.Handler) {
+	v := &slog.LevelVar{}
+	v.Set(level.Level())
+	logger := slog.New((&levelFilter{next: h, level: v}).WithAttrs(attrs))
+	return logger, logHandler{init: level.Level(), current: v, audit: &levelAudit{log: logger}}
+}
+
+// passes records at or above level to next
+type levelFilter struct {
+	next  slog.Handler
+	level slog.Leveler
+}
+
+func (h *levelFilter) Enabled(ctx context.Context, level slog.Level) bool {
+	return level >= h.level.Level() && h.next.Enabled(ctx, level)
+}
+
+func (h *levelFilter) Handle(ctx context.Context, r slog.Record) error {
+	return h.next.Handle(ctx, r)
+}
+
+func (h *levelFilter) WithAttrs(attrs []slog.Attr) slog.Handler {
+	if len(attrs) == 0 {
+		return h
+	}
+	return &levelFilter{next: h.next.WithAttrs(attrs), level: h.level}
+}
+
+func (h *levelFilter) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	return &levelFilter{next: h.next.WithGroup(name), level: h.level}
+}
+
+// attribute key of Subsystem
+const SubsystemKey = "subsystem"
+
+// returns a child of log with the attribute SubsystemKey=name, which only logs
+// records at or above minLevel, e.g. to quiet a noisy subsystem at WARN.
+// The level of log (e.g. changed with the http.Handler) still applies,
+// so the effective level is the higher of the two
+func Subsystem(log *slog.Logger, name string, minLevel slog.Level) *slog.Logger {
+	h := &levelFilter{next: log.Handler(), level: minLevel}
+	return slog.New(h.WithAttrs([]slog.Attr{slog.String(SubsystemKey, name)}))
+}
@@ -0,0 +1,68 @@
+package slogging
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestSubsystem(t *testing.T) {
+	var buf bytes.Buffer
+	log, h := CreateWithOptions(slog.HandlerOptions{Level: slog.LevelDebug}, false,
+		WithWriter(&buf), WithoutTime())
+	db := Subsystem(log, "db", slog.LevelWarn)
+
+	setLevel := func(lvl string) {
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest(http.MethodPut, "/log/"+lvl, nil))
+		if w.Code != http.StatusAccepted {
+			t.Fatalf("failed to set level %s: %d", lvl, w.Code)
+		}
+		buf.Reset()
+	}
+
+	for _, tc := range []struct {
+		level   string
+		enabled map[slog.Level]bool
+	}{
+		// the floor of the subsystem applies below it
+		{"debug", map[slog.Level]bool{slog.LevelInfo: false, slog.LevelWarn: true, slog.LevelError: true}},
+		{"warn", map[slog.Level]bool{slog.LevelInfo: false, slog.LevelWarn: true, slog.LevelError: true}},
+		// the level of the logger applies above it
+		{"error", map[slog.Level]bool{slog.LevelInfo: false, slog.LevelWarn: false, slog.LevelError: true}},
+	} {
+		setLevel(tc.level)
+		for lvl, expected := range tc.enabled {
+			if enabled := db.Enabled(context.Background(), lvl); enabled != expected {
+				t.Errorf("level %s: expected %s enabled %v, got %v", tc.level, lvl, expected, enabled)
+			}
+		}
+	}
+
+	setLevel("debug")
+	db.Info("dropped")
+	db.Warn("slow query")
+	log.Debug("kept")
+	expected := "level=WARN msg=\"slow query\" subsystem=db\n" +
+		"level=DEBUG msg=kept\n"
+	if buf.String() != expected {
+		t.Errorf("expected:\n%s\ngot:\n%s", expected, buf.String())
+	}
+}
+
+func TestCreateWithHandler(t *testing.T) {
+	var buf bytes.Buffer
+	log, h := CreateWithHandler(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: LevelTrace}), slog.LevelInfo)
+	log.Debug("dropped")
+	if buf.Len() != 0 {
+		t.Errorf("expected DEBUG dropped at INFO, got %s", buf.String())
+	}
+
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPut, "/log/debug", nil))
+	if !log.Enabled(context.Background(), slog.LevelDebug) {
+		t.Error("expected DEBUG enabled after setting the level")
+	}
+}
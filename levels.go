@@ -0,0 +1,125 @@
+package slogging
+
+import (
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+)
+
+const (
+	// level below DEBUG, rendered as TRACE
+	LevelTrace = slog.LevelDebug - 4
+	// level used by Fatal and Panic, rendered as FATAL
+	LevelFatal = slog.LevelError + 4
+)
+
+// names of the custom levels, as rendered by NamedLevels and levelName
+var levelNames = map[slog.Level]string{
+	LevelTrace: "TRACE",
+	LevelFatal: "FATAL"}
+
+// name of a level as rendered in log records, e.g. TRACE for LevelTrace,
+// otherwise as slog.Level.String. Used for level responses and audit records
+func levelName(l slog.Level) string {
+	if name, ok := levelNames[l]; ok {
+		return name
+	}
+	return l.String()
+}
+
+// returns a ReplaceAttr func for slog.HandlerOptions, which renders the level
+// attribute with a name for custom levels: TRACE for LevelTrace and FATAL for
+// LevelFatal. Names in extra are added to (or override) these.
+// Other levels are rendered as usual, e.g. "INFO+2".
+func NamedLevels(extra map[slog.Level]string) func(groups []string, a slog.Attr) slog.Attr {
+	names := make(map[slog.Level]string, len(levelNames)+len(extra))
+	for lvl, name := range levelNames {
+		names[lvl] = name
+	}
+	for lvl, name := range extra {
+		names[lvl] = name
+	}
+
+	return func(groups []string, a slog.Attr) slog.Attr {
+		if len(groups) > 0 || a.Key != slog.LevelKey {
+			return a
+		}
+		lvl, ok := a.Value.Any().(slog.Level)
+		if !ok {
+			return a
+		}
+		if name, ok := names[lvl]; ok {
+			a.Value = slog.StringValue(name)
+		}
+		return a
+	}
+}
+
+// level names accepted (case-insensitive) by parseLevelName in addition to
+// those of slog.Level
+var levelAliases = map[string]slog.Level{
+	"trace":   LevelTrace,
+	"warning": slog.LevelWarn,
+	"err":     slog.LevelError,
+	"fatal":   LevelFatal}
+
+// description of the level forms accepted by parseLevelName
+const acceptedLevels = "a name (trace, debug, info, warn/warning, error/err or fatal), " +
+	"optionally with an offset (e.g. INFO+2 or ERROR-1), or an integer (e.g. 4)"
+
+// parse level name, e.g. "debug" or "WARN", optionally with an offset,
+// e.g. "INFO+2", or an integer as slog.Level, e.g. "-4".
+// Also accepts the names of levelAliases
+func parseLevelName(s string) (slog.Level, error) {
+	if n, err := strconv.Atoi(s); err == nil {
+		return slog.Level(n), nil
+	}
+
+	name, offset := s, 0
+	if i := strings.IndexAny(s, "+-"); i > 0 {
+		n, err := strconv.Atoi(s[i:])
+		if err != nil {
+			return 0, fmt.Errorf("unknown log level %q, must be %s", s, acceptedLevels)
+		}
+		name, offset = s[:i], n
+	}
+	if lvl, ok := levelAliases[strings.ToLower(name)]; ok {
+		return lvl + slog.Level(offset), nil
+	}
+
+	var lvl slog.Level
+	if err := lvl.UnmarshalText([]byte(s)); err != nil {
+		return lvl, fmt.Errorf("unknown log level %q, must be %s", s, acceptedLevels)
+	}
+	return lvl, nil
+}
+
+// compose ReplaceAttr funcs, applying them left to right, each to the result
+// of the previous. Stops if a func returns the empty Attr, dropping the attribute.
+// nil funcs are skipped, and nil is returned if no funcs remain.
+// Used to combine the user ReplaceAttr with those of the options
+func ChainReplaceAttr(fs ...func([]string, slog.Attr) slog.Attr) func([]string, slog.Attr) slog.Attr {
+	var xs []func([]string, slog.Attr) slog.Attr
+	for _, f := range fs {
+		if f != nil {
+			xs = append(xs, f)
+		}
+	}
+	switch len(xs) {
+	case 0:
+		return nil
+	case 1:
+		return xs[0]
+	}
+
+	return func(groups []string, a slog.Attr) slog.Attr {
+		for _, f := range xs {
+			a = f(groups, a)
+			if a.Equal(slog.Attr{}) {
+				return a
+			}
+		}
+		return a
+	}
+}
@@ -1,44 +1,175 @@
 package slogging
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
+	"io"
 	"log/slog"
+	"math"
 	"net/http"
 	"os"
-	"runtime/debug"
+	"path"
+	"slices"
 	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
 )
 
-// will log to ERROR+4 and call os.Exit(1)
+// exit function called by Fatal and friends. Replaced in tests
+var exitFunc = os.Exit
+
+// will log to ERROR+4, Flush and call os.Exit(1)
 func Fatal(log *slog.Logger, message string, args ...any) {
-	log.Log(context.Background(), slog.LevelError+4, message, args...)
-	os.Exit(1)
+	fatal(context.Background(), 1, log, message, args...)
+}
+
+// like Fatal, but passes ctx to the logger so context-scoped attributes are kept.
+// Will exit with code 1
+func FatalContext(ctx context.Context, log *slog.Logger, message string, args ...any) {
+	fatal(ctx, 1, log, message, args...)
+}
+
+// like Fatal, but calls os.Exit with the specified code
+func FatalCode(code int, log *slog.Logger, message string, args ...any) {
+	fatal(context.Background(), code, log, message, args...)
+}
+
+// must be called directly by the exported Fatal funcs, for the source to be their caller
+func fatal(ctx context.Context, code int, log *slog.Logger, message string, args ...any) {
+	// skip runtime.Callers, logSkip, fatal and the exported func
+	logSkip(ctx, log, LevelFatal, 4, message, args...)
+	_ = Flush()
+	exitFunc(code)
+}
+
+// will log to ERROR+4 and panic with a *PanicValue holding message and args
+func Panic(log *slog.Logger, message string, args ...any) {
+	// skip runtime.Callers, logSkip and Panic
+	logSkip(context.Background(), log, LevelFatal, 3, message, args...)
+	panic(&PanicValue{Message: message, Args: args})
+}
+
+// value passed to panic by Panic
+type PanicValue struct {
+	Message string
+	Args    []any
+}
+
+// message followed by args as key=value pairs
+func (p *PanicValue) Error() string {
+	r := slog.NewRecord(time.Time{}, LevelFatal, p.Message, 0)
+	r.Add(p.Args...)
+
+	var sb strings.Builder
+	sb.WriteString(p.Message)
+	r.Attrs(func(a slog.Attr) bool {
+		fmt.Fprintf(&sb, " %s", a)
+		return true
+	})
+	return sb.String()
 }
 
-// create logger with options and attributes
+// create logger with options and attributes.
+// Levels LevelTrace and LevelFatal are rendered as TRACE and FATAL.
 // returns a http Handler which can be used to get current log level and
 // update it dynamically.
 // the Handler must be mapped to a path prefix e.g. with gorilla mux:
 // r := mux.NewRouter()
 // r.PathPrefix("/log").Handler(logHandler)
+// GET /log returns the level, PUT or POST /log/{level} sets it and
+// DELETE /log (or PUT or POST /log/reset) resets it to the initial level.
+// PUT or POST /log/format/json (or text) switches the output format.
+// Changes are logged with the requester to the logger, regardless of its
+// level, at most once per second: further changes within a second are
+// coalesced into one record at the end of it.
+// The http.Handler is safe for concurrent use, also while logging: the level
+// and format are read atomically for each record, and concurrent changes are
+// applied one at a time, each reporting the level it replaced. The initial
+// level used for reset never changes.
 func Create(opts slog.HandlerOptions, jsonOutput bool, attrs ...slog.Attr) (*slog.Logger, http.Handler) {
+	return CreateWithWriter(os.Stderr, opts, jsonOutput, attrs...)
+}
+
+// create logger (like Create) writing to w instead of os.Stderr
+func CreateWithWriter(w io.Writer, opts slog.HandlerOptions, jsonOutput bool, attrs ...slog.Attr) (*slog.Logger, http.Handler) {
+	return CreateWithOptions(opts, jsonOutput, WithWriter(w), WithAttrs(attrs...))
+}
+
+// create logger (like Create), also returning the level var used by the logger
+// and changed by the http.Handler. Use it to get or set the level directly
+func CreateWithVar(opts slog.HandlerOptions, jsonOutput bool, attrs ...slog.Attr) (*slog.Logger, http.Handler, *slog.LevelVar) {
+	logger, h := Create(opts, jsonOutput, attrs...)
+	return logger, h, h.(logHandler).current
+}
+
+// create logger (like Create) configured with options.
+// Without options, it writes to os.Stderr with no attributes
+func CreateWithOptions(opts slog.HandlerOptions, jsonOutput bool, options ...Option) (*slog.Logger, http.Handler) {
+	cfg := newConfig(options)
+	if cfg.fixedOrder && opts.AddSource && cfg.sourceLevel == nil {
+		all := slog.Level(math.MinInt)
+		cfg.sourceLevel = &all
+	}
+	if cfg.dropTimeout > 0 {
+		cfg.writer = newBackpressureWriter(cfg.writer, cfg.dropTimeout, cfg.dropStats, cfg.onError)
+	}
+
 	v := slog.LevelVar{}
 	v.Set(opts.Level.Level())
 
 	o := &slog.HandlerOptions{
 		Level:       &v,
-		AddSource:   opts.AddSource,
-		ReplaceAttr: opts.ReplaceAttr}
+		AddSource:   opts.AddSource && cfg.sourceLevel == nil,
+		ReplaceAttr: cfg.replaceAttr(opts.ReplaceAttr)}
 
 	h := logHandler{
-		init:    opts.Level.Level(),
-		current: &v}
+		init:         opts.Level.Level(),
+		current:      &v,
+		format:       &atomic.Bool{},
+		authorize:    cfg.authorize,
+		authorizeGet: cfg.authorizeGet,
+		corsOrigins:  cfg.corsOrigins,
+		audit:        &levelAudit{}}
+	h.format.Store(jsonOutput)
+	if cfg.registry != nil {
+		if err := cfg.registry.set(cfg.name, h); err != nil && cfg.onError != nil {
+			cfg.onError(err)
+		}
+	}
 
-	if jsonOutput {
-		return slog.New(slog.NewJSONHandler(os.Stderr, o).WithAttrs(attrs)), h
+	jsonWriter := cfg.writer
+	if cfg.prettyJSON {
+		jsonWriter = &prettyJSONWriter{w: jsonWriter}
+	}
+	if cfg.validateJSON {
+		// before indenting, which passes invalid JSON through
+		jsonWriter = &validatingJSONWriter{w: jsonWriter}
+	}
+	var textHandler slog.Handler
+	if cfg.color.enabled(cfg.writer) {
+		textHandler = NewConsoleHandler(cfg.writer, o)
+	} else {
+		textHandler = slog.NewTextHandler(cfg.writer, o)
+	}
+	// the format may be switched with the level handler
+	var handler slog.Handler = newFormatSwitch(h.format, slog.NewJSONHandler(jsonWriter, o), textHandler)
+	if cfg.onError != nil {
+		// innermost, so errors are reported also for records handled
+		// asynchronously by other wrappers
+		handler = &errorHandler{next: handler, onError: cfg.onError}
+	}
+	if cfg.sourceLevel != nil {
+		handler = &sourceHandler{next: handler, minLevel: *cfg.sourceLevel}
+	}
+	for _, wrap := range cfg.wrappers {
+		handler = wrap(handler)
 	}
-	return slog.New(slog.NewTextHandler(os.Stderr, o).WithAttrs(attrs)), h
+	logger := slog.New(handler.WithAttrs(cfg.attrs))
+	h.audit.log = logger
+	return logger, h
 }
 
 // create logger (using Create) and sets the default logger
@@ -48,62 +179,292 @@ func SetDefaults(opts slog.HandlerOptions, jsonOutput bool, attributes ...slog.A
 	return handler
 }
 
+// returns logger with attrs attached to every record, e.g. learned after Create.
+// The level of the returned logger is still controlled by the http.Handler
+// from Create, as it shares the level with log.
+// Set as default with slog.SetDefault(With(slog.Default(), ...))
+func With(log *slog.Logger, attrs ...slog.Attr) *slog.Logger {
+	if len(attrs) == 0 {
+		return log
+	}
+	return slog.New(log.Handler().WithAttrs(attrs))
+}
+
+// methods supported by the level handlers
+const allowedMethods = "GET, PUT, POST, DELETE, OPTIONS"
+
 type logHandler struct {
 	init    slog.Level
 	current *slog.LevelVar
+	// true for JSON output, false for text. nil if not switchable
+	format *atomic.Bool
+
+	// optional, see WithLevelAuth
+	authorize    func(*http.Request) bool
+	authorizeGet bool
+	// optional, see WithCORS
+	corsOrigins []string
+
+	// audit log of changes, written to the logger of the handler
+	audit *levelAudit
 }
 
 func (h logHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.setCORSHeaders(w, r)
+	if !h.authorized(r) {
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+
 	switch r.Method {
 	case http.MethodGet:
-		_, _ = w.Write([]byte(h.current.Level().String()))
+		if acceptsJSON(r) {
+			w.Header().Set("Content-Type", "application/json")
+			_ = json.NewEncoder(w).Encode(h.levelResponse())
+			return
+		}
+		_, _ = w.Write([]byte(levelName(h.current.Level())))
 	case http.MethodPut, http.MethodPost:
-		// extract level from last path of URL
-		xs := strings.Split(r.URL.Path, "/")
-		if len(xs) == 0 {
-			w.WriteHeader(http.StatusBadRequest)
-			_, _ = w.Write([]byte("specify log level as last part of the URL, e.g. PUT /log/debug"))
+		if format, ok := formatFromPath(r.URL.Path); ok {
+			h.setFormat(w, format)
+			return
+		}
+		if isResetPath(r.URL.Path) {
+			prev := h.resetLevel(r)
+			writeLevelChange(w, r, prev, h.init)
 			return
 		}
-		lastPart := xs[len(xs)-1]
-		var lvl slog.Level
-		err := lvl.UnmarshalText([]byte(lastPart))
-		if err != nil {
+		lvl, ok := levelFromRequest(r)
+		if !ok {
 			w.WriteHeader(http.StatusBadRequest)
-			fmt.Fprintf(w, "unknown log level %q", lastPart)
+			_, _ = w.Write([]byte("specify log level as last part of the URL, e.g. PUT /log/debug, " +
+				`or in the body as plain text or JSON, e.g. {"level":"debug"}. ` +
+				"The level must be " + acceptedLevels))
 			return
 		}
-		h.current.Set(lvl)
-		w.WriteHeader(http.StatusAccepted)
-		slog.LogAttrs(context.Background(), slog.LevelInfo, "log level set", slog.String("newLevel", lvl.String()))
+		prev := h.setLevel(r, lvl)
+		writeLevelChange(w, r, prev, lvl)
 
 	case http.MethodDelete:
-		h.current.Set(h.init)
-		w.WriteHeader(http.StatusAccepted)
-		slog.LogAttrs(context.Background(), slog.LevelInfo, "log level reset", slog.String("newLevel", h.init.String()))
+		prev := h.resetLevel(r)
+		writeLevelChange(w, r, prev, h.init)
+	case http.MethodOptions:
+		w.Header().Set("Allow", allowedMethods)
+		w.WriteHeader(http.StatusNoContent)
 	default:
+		w.Header().Set("Allow", allowedMethods)
 		w.WriteHeader(http.StatusMethodNotAllowed)
 	}
 }
 
-// log build info (go version and vcs revision, time and modified) to Info level.
-// Returns true if some build info was found.
-// Remember to build the application without specifying the .go file,
-// e.g. "go build -o main", _not_ "go build -o main main.go"
-// See issue https://github.com/golang/go/issues/51279
-func LogBuildInfo() bool {
-	info, ok := debug.ReadBuildInfo()
-	if !ok {
-		return false
+// whether r is allowed by the authorize func (see WithLevelAuth).
+// OPTIONS is always allowed, and GET unless authorizeGet
+func (h logHandler) authorized(r *http.Request) bool {
+	if h.authorize == nil || r.Method == http.MethodOptions ||
+		(r.Method == http.MethodGet && !h.authorizeGet) {
+		return true
+	}
+	return h.authorize(r)
+}
+
+// set CORS headers, if the Origin of r is allowed
+func (h logHandler) setCORSHeaders(w http.ResponseWriter, r *http.Request) {
+	origin := r.Header.Get("Origin")
+	if origin == "" || !slices.Contains(h.corsOrigins, origin) {
+		return
 	}
+	w.Header().Set("Access-Control-Allow-Origin", origin)
+	w.Header().Set("Access-Control-Allow-Methods", allowedMethods)
+	w.Header().Set("Access-Control-Allow-Headers", "Accept, Authorization, Content-Type")
+	w.Header().Add("Vary", "Origin")
+}
+
+// serializes level changes, so that the previous level reported for each
+// change is the one it replaced. Reading the level (e.g. when logging) is not
+// blocked, as slog.LevelVar is safe for concurrent use
+var levelChangeMu sync.Mutex
+
+// set level and log the change, with the requester if r is not nil.
+// Returns the previous level
+func (h logHandler) setLevel(r *http.Request, lvl slog.Level) slog.Level {
+	prev, _ := h.changeLevel(r, "log level set", func(slog.Level) slog.Level { return lvl })
+	return prev
+}
+
+// reset level to the initial level and log the change, with the requester
+// if r is not nil. Returns the previous level
+func (h logHandler) resetLevel(r *http.Request) slog.Level {
+	prev, _ := h.changeLevel(r, "log level reset", func(slog.Level) slog.Level { return h.init })
+	return prev
+}
+
+// step level to the next standard level below (dir < 0) or above (dir > 0)
+// and log the change. Returns the new level
+func (h logHandler) step(dir int) slog.Level {
+	_, lvl := h.changeLevel(nil, "log level set", func(prev slog.Level) slog.Level { return stepLevel(prev, dir) })
+	return lvl
+}
+
+// set level to next of the current level, as one change, and log the change
+// with msg. Returns the previous and new level
+func (h logHandler) changeLevel(r *http.Request, msg string, next func(slog.Level) slog.Level) (prev, lvl slog.Level) {
+	levelChangeMu.Lock()
+	prev = h.current.Level()
+	lvl = next(prev)
+	h.current.Set(lvl)
+	levelChangeMu.Unlock()
+	h.audit.record(msg, levelChangeAttrs(r, prev, lvl)...)
+	notifyLevelChange(prev, lvl)
+	return prev, lvl
+}
+
+// attributes for auditing a level change
+func levelChangeAttrs(r *http.Request, prev, lvl slog.Level) []slog.Attr {
+	attrs := []slog.Attr{
+		slog.String("newLevel", levelName(lvl)),
+		slog.String("previousLevel", levelName(prev))}
+	if r != nil {
+		attrs = append(attrs, slog.String("remoteAddr", r.RemoteAddr))
+		if xff := r.Header.Get("X-Forwarded-For"); xff != "" {
+			attrs = append(attrs, slog.String("forwardedFor", xff))
+		}
+	}
+	return attrs
+}
+
+// respond 202 with the new and previous level,
+// as JSON if requested with Accept: application/json
+func writeLevelChange(w http.ResponseWriter, r *http.Request, prev, lvl slog.Level) {
+	if acceptsJSON(r) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusAccepted)
+		_ = json.NewEncoder(w).Encode(levelChangeResponse{
+			Level:    levelName(lvl),
+			Previous: levelName(prev)})
+		return
+	}
+	w.WriteHeader(http.StatusAccepted)
+	fmt.Fprintf(w, "%s (previous %s)", levelName(lvl), levelName(prev))
+}
+
+// format from a path ending in /format/{format}, or empty for a path
+// ending in /format
+func formatFromPath(path string) (string, bool) {
+	xs := pathSegments(path)
+	switch {
+	case xs[len(xs)-1] == "format":
+		return "", true
+	case len(xs) >= 2 && xs[len(xs)-2] == "format":
+		return xs[len(xs)-1], true
+	default:
+		return "", false
+	}
+}
+
+// switch output format to "json" or "text"
+func (h logHandler) setFormat(w http.ResponseWriter, format string) {
+	if h.format == nil {
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte("format can not be changed for this logger"))
+		return
+	}
+	if format == "" {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte("missing format, must be json or text"))
+		return
+	}
+	jsonOutput, err := parseFormat(format)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte(err.Error()))
+		return
+	}
+	h.format.Store(jsonOutput)
+	w.WriteHeader(http.StatusAccepted)
+	h.audit.record("log format set", slog.String("newFormat", format))
+}
+
+// parts of the cleaned URL path, so that a trailing or double slash
+// (e.g. /log/debug/ or /log//debug) does not give empty parts,
+// except for the last part of an empty path or "/".
+// The query is not part of the path (see url.URL)
+func pathSegments(p string) []string {
+	p = strings.Trim(path.Clean("/"+p), "/")
+	return strings.Split(p, "/")
+}
+
+// whether the last part of path is "reset" or "default", which resets the level
+// as DELETE does, for clients or proxies not supporting DELETE
+func isResetPath(path string) bool {
+	xs := pathSegments(path)
+	last := xs[len(xs)-1]
+	return strings.EqualFold(last, "reset") || strings.EqualFold(last, "default")
+}
+
+// max size of a request body with a level
+const maxLevelBodySize = 1024
+
+// extract level from last part of the URL path or,
+// if that is not a level, from the body (plain text or JSON {"level": ...})
+func levelFromRequest(r *http.Request) (slog.Level, bool) {
+	xs := pathSegments(r.URL.Path)
+	if lvl, err := parseLevelName(xs[len(xs)-1]); err == nil {
+		return lvl, true
+	}
+
+	if r.Body == nil {
+		return 0, false
+	}
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxLevelBodySize))
+	if err != nil {
+		return 0, false
+	}
+	body = bytes.TrimSpace(body)
+	if len(body) > 0 && body[0] == '{' {
+		var req struct {
+			Level string `json:"level"`
+		}
+		if json.Unmarshal(body, &req) != nil {
+			return 0, false
+		}
+		body = []byte(req.Level)
+	}
+	lvl, err := parseLevelName(string(body))
+	return lvl, err == nil
+}
+
+// JSON response to GET, when requested with Accept: application/json
+type levelResponse struct {
+	Level   string `json:"level"`
+	Default string `json:"default"`
+	// whether each standard level is enabled by the current level
+	Enabled map[string]bool `json:"enabled"`
+}
+
+func (h logHandler) levelResponse() levelResponse {
+	current := h.current.Level()
+	return levelResponse{
+		Level:   levelName(current),
+		Default: levelName(h.init),
+		Enabled: map[string]bool{
+			"DEBUG": slog.LevelDebug >= current,
+			"INFO":  slog.LevelInfo >= current,
+			"WARN":  slog.LevelWarn >= current,
+			"ERROR": slog.LevelError >= current}}
+}
+
+// JSON response to a level change
+type levelChangeResponse struct {
+	Level    string `json:"level"`
+	Previous string `json:"previous"`
+}
 
-	var attrs []slog.Attr
-	attrs = append(attrs, slog.String("goVersion", info.GoVersion))
-	for _, kv := range info.Settings {
-		if strings.HasPrefix(kv.Key, "vcs") {
-			attrs = append(attrs, slog.String(kv.Key, kv.Value))
+// whether any Accept header includes application/json
+func acceptsJSON(r *http.Request) bool {
+	for _, v := range r.Header.Values("Accept") {
+		if strings.Contains(v, "application/json") {
+			return true
 		}
 	}
-	slog.LogAttrs(context.Background(), slog.LevelInfo, "build info", attrs...)
-	return true
+	return false
 }
@@ -0,0 +1,363 @@
+package slogging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestLevelHandlerConcurrent(t *testing.T) {
+	m := NewMultiLevelHandler()
+	log, h := CreateWithOptions(slog.HandlerOptions{Level: slog.LevelInfo}, true,
+		WithWriter(io.Discard), WithName(m, "app"))
+	lh := h.(logHandler)
+
+	requests := []struct {
+		handler      http.Handler
+		method, path string
+	}{
+		{h, http.MethodPut, "/log/debug"},
+		{h, http.MethodGet, "/log"},
+		{h, http.MethodDelete, "/log"},
+		{h, http.MethodPost, "/log/format/text"},
+		{m, http.MethodPut, "/log/app/warn"},
+		{m, http.MethodGet, "/log"},
+		{m, http.MethodDelete, "/log/app"},
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			l := log.With("worker", i)
+			for j := 0; j < 200; j++ {
+				l.Debug("debug", "j", j)
+				l.Info("info", "j", j)
+			}
+		}(i)
+	}
+	for _, req := range requests {
+		wg.Add(1)
+		go func(handler http.Handler, method, path string) {
+			defer wg.Done()
+			for j := 0; j < 50; j++ {
+				handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(method, path, nil))
+			}
+		}(req.handler, req.method, req.path)
+	}
+	for _, dir := range []int{-1, 1} {
+		wg.Add(1)
+		go func(dir int) {
+			defer wg.Done()
+			for j := 0; j < 50; j++ {
+				lh.step(dir)
+			}
+		}(dir)
+	}
+	wg.Wait()
+
+	lh.resetLevel(nil)
+	if log.Enabled(context.Background(), slog.LevelDebug) || !log.Enabled(context.Background(), slog.LevelInfo) {
+		t.Errorf("expected level INFO after reset, got %s", lh.current.Level())
+	}
+}
+
+func TestLevelStepConcurrentNotLost(t *testing.T) {
+	_, h := CreateWithOptions(slog.HandlerOptions{Level: slog.LevelError}, false, WithWriter(io.Discard))
+	lh := h.(logHandler)
+
+	for i := 0; i < 100; i++ {
+		lh.setLevel(nil, slog.LevelError)
+		var wg sync.WaitGroup
+		// from ERROR, three steps down reach DEBUG unless a step is lost
+		for j := 0; j < 3; j++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				lh.step(-1)
+			}()
+		}
+		wg.Wait()
+		if lvl := lh.current.Level(); lvl != slog.LevelDebug {
+			t.Fatalf("expected DEBUG after 3 concurrent steps down from ERROR, got %s", lvl)
+		}
+	}
+}
+
+func TestLevelChangeAuditCoalesced(t *testing.T) {
+	defer func(d time.Duration) { levelAuditInterval = d }(levelAuditInterval)
+	levelAuditInterval = 100 * time.Millisecond
+
+	var buf syncBuffer
+	_, h := CreateWithOptions(slog.HandlerOptions{Level: slog.LevelInfo}, false,
+		WithWriter(&buf), WithoutTime())
+	for _, lvl := range []string{"error", "debug", "warn"} {
+		r := httptest.NewRequest(http.MethodPut, "/log/"+lvl, nil)
+		r.RemoteAddr = "10.0.0.1:1234"
+		h.ServeHTTP(httptest.NewRecorder(), r)
+	}
+
+	// the first change is written, although the level is raised to ERROR
+	expected := `level=INFO msg="log level set" newLevel=ERROR previousLevel=INFO remoteAddr=10.0.0.1:1234` + "\n"
+	if s := buf.String(); s != expected {
+		t.Fatalf("expected:\n%s\ngot:\n%s", expected, s)
+	}
+
+	time.Sleep(3 * levelAuditInterval)
+	expected += `level=INFO msg="log level set" newLevel=WARN previousLevel=DEBUG remoteAddr=10.0.0.1:1234 coalesced=2` + "\n"
+	if s := buf.String(); s != expected {
+		t.Errorf("expected:\n%s\ngot:\n%s", expected, s)
+	}
+}
+
+// bytes.Buffer safe for concurrent use
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+func TestLevelHandlerPaths(t *testing.T) {
+	for _, tc := range []struct {
+		method, path string
+		status       int
+		level        slog.Level
+	}{
+		{http.MethodPut, "/log", http.StatusBadRequest, slog.LevelInfo},
+		{http.MethodPut, "/log/", http.StatusBadRequest, slog.LevelInfo},
+		{http.MethodPut, "/log/debug", http.StatusAccepted, slog.LevelDebug},
+		{http.MethodPut, "/log/debug/", http.StatusAccepted, slog.LevelDebug},
+		{http.MethodPut, "/log//debug", http.StatusAccepted, slog.LevelDebug},
+		{http.MethodPut, "/log/debug?foo=bar", http.StatusAccepted, slog.LevelDebug},
+		{http.MethodPost, "/log/WARN", http.StatusAccepted, slog.LevelWarn},
+		{http.MethodPut, "/log/debug-", http.StatusBadRequest, slog.LevelInfo},
+		{http.MethodPut, "/log/INFO+2", http.StatusAccepted, slog.LevelInfo + 2},
+		{http.MethodPut, "/log/warning+1", http.StatusAccepted, slog.LevelWarn + 1},
+		{http.MethodPut, "/log/error-1", http.StatusAccepted, slog.LevelError - 1},
+		{http.MethodPut, "/log/trace", http.StatusAccepted, LevelTrace},
+		{http.MethodPut, "/log/-4", http.StatusAccepted, slog.LevelDebug},
+		{http.MethodPut, "/log/verbose", http.StatusBadRequest, slog.LevelInfo},
+		{http.MethodPut, "/log/reset", http.StatusAccepted, slog.LevelInfo},
+		{http.MethodPost, "/log/default/", http.StatusAccepted, slog.LevelInfo},
+		{http.MethodPut, "/log/format", http.StatusBadRequest, slog.LevelInfo},
+		{http.MethodPut, "/log/format/", http.StatusBadRequest, slog.LevelInfo},
+		{http.MethodPut, "/log/format/xml", http.StatusBadRequest, slog.LevelInfo},
+		{http.MethodPut, "/log/format/json", http.StatusAccepted, slog.LevelInfo},
+		{http.MethodPut, "/log/format/text?x=1", http.StatusAccepted, slog.LevelInfo},
+		{http.MethodDelete, "/log/", http.StatusAccepted, slog.LevelInfo},
+		{http.MethodPatch, "/log/debug", http.StatusMethodNotAllowed, slog.LevelInfo},
+	} {
+		t.Run(tc.method+" "+tc.path, func(t *testing.T) {
+			_, h := CreateWithOptions(slog.HandlerOptions{Level: slog.LevelInfo}, false, WithWriter(io.Discard))
+
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, httptest.NewRequest(tc.method, tc.path, nil))
+
+			if w.Code != tc.status {
+				t.Errorf("expected status %d, got %d: %s", tc.status, w.Code, w.Body)
+			}
+			if lvl := h.(logHandler).current.Level(); lvl != tc.level {
+				t.Errorf("expected level %s, got %s", tc.level, lvl)
+			}
+		})
+	}
+}
+
+func TestLevelHandlerMissingFormat(t *testing.T) {
+	_, h := CreateWithOptions(slog.HandlerOptions{Level: slog.LevelInfo}, false, WithWriter(io.Discard))
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodPut, "/log/format", nil))
+
+	if w.Code != http.StatusBadRequest || w.Body.String() != "missing format, must be json or text" {
+		t.Errorf("expected 400 with missing format, got %d: %s", w.Code, w.Body)
+	}
+}
+
+func TestPanic(t *testing.T) {
+	var buf bytes.Buffer
+	log, _ := CreateWithOptions(slog.HandlerOptions{Level: slog.LevelInfo}, false,
+		WithWriter(&buf), WithoutTime())
+
+	var logged string
+	var recovered any
+	func() {
+		defer func() {
+			// runs after the record is written, while unwinding
+			logged = buf.String()
+			recovered = recover()
+		}()
+		Panic(log, "boom", "id", 42)
+	}()
+
+	if expected := "level=FATAL msg=boom id=42\n"; logged != expected {
+		t.Errorf("expected %q logged before unwinding, got %q", expected, logged)
+	}
+	p, ok := recovered.(*PanicValue)
+	if !ok {
+		t.Fatalf("expected *PanicValue, got %T: %v", recovered, recovered)
+	}
+	if p.Message != "boom" || len(p.Args) != 2 || p.Args[0] != "id" || p.Args[1] != 42 {
+		t.Errorf("unexpected panic value %+v", p)
+	}
+	if p.Error() != "boom id=42" {
+		t.Errorf("expected error %q, got %q", "boom id=42", p.Error())
+	}
+}
+
+func TestWrapperSourceIsCaller(t *testing.T) {
+	var buf bytes.Buffer
+	log, _ := CreateWithOptions(slog.HandlerOptions{Level: slog.LevelDebug, AddSource: true}, true, WithWriter(&buf))
+	defer func(f func(int)) { exitFunc = f }(exitFunc)
+	exitFunc = func(int) {}
+	ctx := context.Background()
+
+	for _, tc := range []struct {
+		name string
+		log  func() int // logs and returns the line logged from
+	}{
+		{"Fatal", func() int {
+			line := callerLine() + 1
+			Fatal(log, "m")
+			return line
+		}},
+		{"FatalContext", func() int {
+			line := callerLine() + 1
+			FatalContext(ctx, log, "m")
+			return line
+		}},
+		{"FatalCode", func() int {
+			line := callerLine() + 1
+			FatalCode(70, log, "m")
+			return line
+		}},
+		{"Panic", func() (line int) {
+			defer func() { _ = recover() }()
+			line = callerLine() + 1
+			Panic(log, "m")
+			return line
+		}},
+		{"Debugf", func() int {
+			line := callerLine() + 1
+			Debugf(log, "m %d", 1)
+			return line
+		}},
+		{"WarnfContext", func() int {
+			line := callerLine() + 1
+			WarnfContext(ctx, log, "m %d", 1)
+			return line
+		}},
+		{"LogAt", func() int {
+			line := callerLine() + 1
+			LogAt(ctx, log, slog.LevelWarn, "m")
+			return line
+		}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			buf.Reset()
+			line := tc.log()
+
+			var rec struct {
+				Source slog.Source `json:"source"`
+			}
+			if err := json.Unmarshal(buf.Bytes(), &rec); err != nil {
+				t.Fatalf("invalid JSON %q: %v", buf.Bytes(), err)
+			}
+			if filepath.Base(rec.Source.File) != "log_test.go" || rec.Source.Line != line {
+				t.Errorf("expected source log_test.go:%d, got %s:%d", line, rec.Source.File, rec.Source.Line)
+			}
+		})
+	}
+}
+
+func TestFatalExitCode(t *testing.T) {
+	var buf bytes.Buffer
+	log, _ := CreateWithOptions(slog.HandlerOptions{Level: slog.LevelInfo}, false,
+		WithWriter(&buf), WithoutTime())
+	defer func(f func(int)) { exitFunc = f }(exitFunc)
+	var codes []int
+	exitFunc = func(code int) { codes = append(codes, code) }
+
+	Fatal(log, "a")
+	FatalContext(ContextWithAttrs(context.Background(), slog.Int("id", 1)), log, "b")
+	FatalCode(70, log, "c", "err", "failed")
+
+	if len(codes) != 3 || codes[0] != 1 || codes[1] != 1 || codes[2] != 70 {
+		t.Errorf("expected exit codes [1 1 70], got %v", codes)
+	}
+	expected := "level=FATAL msg=a\n" +
+		"level=FATAL msg=b\n" +
+		"level=FATAL msg=c err=failed\n"
+	if buf.String() != expected {
+		t.Errorf("expected:\n%s\ngot:\n%s", expected, buf.String())
+	}
+}
+
+func TestLevelHandlerCustomLevelNames(t *testing.T) {
+	var buf syncBuffer
+	_, h := CreateWithOptions(slog.HandlerOptions{Level: slog.LevelInfo}, false,
+		WithWriter(&buf), WithoutTime())
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodPut, "/log/fatal", nil))
+	if expected := "FATAL (previous INFO)"; w.Body.String() != expected {
+		t.Errorf("expected %q, got %q", expected, w.Body)
+	}
+
+	w = httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodPut, "/log/trace", nil)
+	r.Header.Set("Accept", "application/json")
+	h.ServeHTTP(w, r)
+	var change levelChangeResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &change); err != nil {
+		t.Fatal(err)
+	}
+	if change.Level != "TRACE" || change.Previous != "FATAL" {
+		t.Errorf("expected TRACE (previous FATAL), got %+v", change)
+	}
+
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/log", nil))
+	if w.Body.String() != "TRACE" {
+		t.Errorf("expected TRACE, got %q", w.Body)
+	}
+
+	w = httptest.NewRecorder()
+	r = httptest.NewRequest(http.MethodGet, "/log", nil)
+	r.Header.Set("Accept", "application/json")
+	h.ServeHTTP(w, r)
+	var current levelResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &current); err != nil {
+		t.Fatal(err)
+	}
+	if current.Level != "TRACE" || current.Default != "INFO" {
+		t.Errorf("expected level TRACE and default INFO, got %+v", current)
+	}
+
+	// the first change is audited right away
+	expected := `level=INFO msg="log level set" newLevel=FATAL previousLevel=INFO remoteAddr=192.0.2.1:1234`
+	if s := buf.String(); !strings.HasPrefix(s, expected) {
+		t.Errorf("expected audit record:\n%s\ngot:\n%s", expected, s)
+	}
+}
@@ -0,0 +1,105 @@
+package slogging
+
+import (
+	"context"
+	"log/slog"
+)
+
+// key of the attribute with the number of attributes dropped by WithMaxAttrs
+const AttrsTruncatedKey = "attrs_truncated"
+
+// keeps at most max attributes per record, see WithMaxAttrs
+type maxAttrsHandler struct {
+	next slog.Handler
+	max  int
+	// attributes added with WithAttrs, and dropped from them
+	used    int
+	dropped int
+}
+
+func (h *maxAttrsHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+func (h *maxAttrsHandler) Handle(ctx context.Context, r slog.Record) error {
+	if h.dropped == 0 && h.used+countRecordAttrs(r) <= h.max {
+		return h.next.Handle(ctx, r)
+	}
+
+	kept, dropped := limitAttrs(recordAttrs(r), h.max-h.used)
+	nr := slog.NewRecord(r.Time, r.Level, r.Message, r.PC)
+	nr.AddAttrs(kept...)
+	nr.AddAttrs(slog.Int(AttrsTruncatedKey, h.dropped+dropped))
+	return h.next.Handle(ctx, nr)
+}
+
+func (h *maxAttrsHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	if len(attrs) == 0 {
+		return h
+	}
+	kept, dropped := limitAttrs(attrs, h.max-h.used)
+	h2 := *h
+	h2.used += countAttrs(kept)
+	h2.dropped += dropped
+	if len(kept) > 0 {
+		h2.next = h.next.WithAttrs(kept)
+	}
+	return &h2
+}
+
+func (h *maxAttrsHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	h2 := *h
+	h2.next = h.next.WithGroup(name)
+	return &h2
+}
+
+// number of attributes of r, counted as by limitAttrs
+func countRecordAttrs(r slog.Record) int {
+	n := 0
+	r.Attrs(func(a slog.Attr) bool {
+		n += countAttrs([]slog.Attr{a})
+		return true
+	})
+	return n
+}
+
+// number of attributes, counting those in groups (at any depth) but not the
+// groups themselves
+func countAttrs(attrs []slog.Attr) int {
+	n := 0
+	for _, a := range attrs {
+		if a.Value.Kind() == slog.KindGroup {
+			n += countAttrs(a.Value.Group())
+		} else {
+			n++
+		}
+	}
+	return n
+}
+
+// the first limit attributes of attrs, counting the attributes in groups (at
+// any depth) but not the groups themselves, and the number dropped.
+// Groups left empty are dropped
+func limitAttrs(attrs []slog.Attr, limit int) (kept []slog.Attr, dropped int) {
+	for _, a := range attrs {
+		if a.Value.Kind() == slog.KindGroup {
+			group, d := limitAttrs(a.Value.Group(), limit)
+			dropped += d
+			if len(group) > 0 {
+				limit -= countAttrs(group)
+				kept = append(kept, slog.Attr{Key: a.Key, Value: slog.GroupValue(group...)})
+			}
+			continue
+		}
+		if limit <= 0 {
+			dropped++
+			continue
+		}
+		limit--
+		kept = append(kept, a)
+	}
+	return kept, dropped
+}
@@ -0,0 +1,88 @@
+package slogging
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"sync/atomic"
+)
+
+// names of the level buckets counted by LevelCounts
+var levelBuckets = [...]string{"TRACE", "DEBUG", "INFO", "WARN", "ERROR", "FATAL"}
+
+// LevelCounts counts records by level, see MetricsHandler.
+// Levels are counted in the bucket of the nearest standard level at or below,
+// e.g. INFO+2 as INFO.
+// It implements expvar.Var, so may be published, e.g. expvar.Publish("logRecords", counts)
+type LevelCounts struct {
+	counters [len(levelBuckets)]atomic.Uint64
+}
+
+// current counts by level name
+func (c *LevelCounts) Stats() map[string]uint64 {
+	m := make(map[string]uint64, len(levelBuckets))
+	for i, name := range levelBuckets {
+		m[name] = c.counters[i].Load()
+	}
+	return m
+}
+
+// counts as JSON object, see expvar.Var
+func (c *LevelCounts) String() string {
+	b, _ := json.Marshal(c.Stats())
+	return string(b)
+}
+
+func (c *LevelCounts) add(level slog.Level) {
+	var i int
+	switch {
+	case level >= LevelFatal:
+		i = 5
+	case level >= slog.LevelError:
+		i = 4
+	case level >= slog.LevelWarn:
+		i = 3
+	case level >= slog.LevelInfo:
+		i = 2
+	case level >= slog.LevelDebug:
+		i = 1
+	}
+	c.counters[i].Add(1)
+}
+
+// MetricsHandler is a slog.Handler counting records by level before passing
+// them to the next handler. Only enabled records are counted
+type MetricsHandler struct {
+	next   slog.Handler
+	counts *LevelCounts
+}
+
+// create MetricsHandler counting in counts. If counts is nil, new counts are used
+func NewMetricsHandler(next slog.Handler, counts *LevelCounts) *MetricsHandler {
+	if counts == nil {
+		counts = &LevelCounts{}
+	}
+	return &MetricsHandler{next: next, counts: counts}
+}
+
+// current counts by level name, shared with handlers derived from h
+func (h *MetricsHandler) Stats() map[string]uint64 {
+	return h.counts.Stats()
+}
+
+func (h *MetricsHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+func (h *MetricsHandler) Handle(ctx context.Context, r slog.Record) error {
+	h.counts.add(r.Level)
+	return h.next.Handle(ctx, r)
+}
+
+func (h *MetricsHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &MetricsHandler{next: h.next.WithAttrs(attrs), counts: h.counts}
+}
+
+func (h *MetricsHandler) WithGroup(name string) slog.Handler {
+	return &MetricsHandler{next: h.next.WithGroup(name), counts: h.counts}
+}
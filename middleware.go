@@ -0,0 +1,165 @@
+package slogging
+
+import (
+	"bufio"
+	"errors"
+	"log/slog"
+	"net"
+	"net/http"
+	"runtime/debug"
+	"time"
+)
+
+// http middleware logging each request with method, path, status, size and duration.
+// Logs at INFO, or WARN for 4xx and ERROR for 5xx status codes.
+// The start of each request is logged at DEBUG.
+func RequestLogger(log *slog.Logger) func(http.Handler) http.Handler {
+	return RequestLoggerWithBudget(log, 0)
+}
+
+// like RequestLogger, also setting a log budget of limit records for each
+// request (see ContextWithLogBudget), if limit > 0. The request is logged
+// outside the budget, so it is always logged.
+// Override the budget for some routes with LogBudget
+func RequestLoggerWithBudget(log *slog.Logger, limit int) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := r.Context()
+			start := time.Now()
+			log.LogAttrs(ctx, slog.LevelDebug, "request started",
+				slog.String("method", r.Method),
+				slog.String("path", r.URL.Path))
+
+			rw := &responseWriter{ResponseWriter: w}
+			if limit > 0 {
+				r = r.WithContext(ContextWithLogBudget(ctx, limit))
+			}
+			next.ServeHTTP(rw, r)
+
+			status := rw.status
+			if status == 0 {
+				status = http.StatusOK
+			}
+			log.LogAttrs(ctx, statusLevel(status), "request",
+				slog.String("method", r.Method),
+				slog.String("path", r.URL.Path),
+				slog.Int("status", status),
+				slog.Int64("size", rw.size),
+				slog.Duration("duration", time.Since(start)))
+		})
+	}
+}
+
+func statusLevel(status int) slog.Level {
+	switch {
+	case status >= 500:
+		return slog.LevelError
+	case status >= 400:
+		return slog.LevelWarn
+	default:
+		return slog.LevelInfo
+	}
+}
+
+// wraps http.ResponseWriter to capture status code and bytes written.
+// Supports http.Flusher and http.Hijacker when the wrapped writer does
+type responseWriter struct {
+	http.ResponseWriter
+	status int
+	size   int64
+}
+
+func (w *responseWriter) WriteHeader(status int) {
+	if w.status == 0 {
+		w.status = status
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *responseWriter) Write(p []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	n, err := w.ResponseWriter.Write(p)
+	w.size += int64(n)
+	return n, err
+}
+
+func (w *responseWriter) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		if w.status == 0 {
+			w.status = http.StatusOK
+		}
+		f.Flush()
+	}
+}
+
+func (w *responseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	h, ok := w.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, errors.New("http.Hijacker not supported by underlying ResponseWriter")
+	}
+	if w.status == 0 {
+		w.status = http.StatusSwitchingProtocols
+	}
+	return h.Hijack()
+}
+
+// for http.ResponseController
+func (w *responseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// default request header for LevelOverride
+const LevelHeader = "X-Log-Level"
+
+// http middleware setting the level for a request's context (see ContextWithLevel)
+// from the request header (e.g. LevelHeader). Only applies to loggers with a
+// ContextHandler (e.g. with the WithContextHandler option).
+// allow is called with the requested level and must return true for it to be
+// applied, e.g. checking an allow-list or authorization.
+// Requests with an invalid level are passed on unchanged
+func LevelOverride(header string, allow func(r *http.Request, level slog.Level) bool) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if s := r.Header.Get(header); s != "" {
+				if lvl, err := parseLevelName(s); err == nil && allow(r, lvl) {
+					r = r.WithContext(ContextWithLevel(r.Context(), lvl))
+				}
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// http middleware recovering panics in next, which are logged at LevelFatal
+// with the panic value, method, path and the stack, and answered with 500
+// (unless the response was already started).
+// Panics with http.ErrAbortHandler are passed on, as they are used to abort
+// a response and are not logged by the http.Server. Place it inside
+// RequestLogger for the request to also be logged with status 500
+func Recoverer(log *slog.Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			rw := &responseWriter{ResponseWriter: w}
+			defer func() {
+				v := recover()
+				if v == nil {
+					return
+				}
+				if v == http.ErrAbortHandler {
+					panic(v)
+				}
+				log.LogAttrs(r.Context(), LevelFatal, "panic recovered",
+					slog.Any("panic", v),
+					slog.String("method", r.Method),
+					slog.String("path", r.URL.Path),
+					slog.String("stack", string(debug.Stack())))
+				if rw.status == 0 {
+					w.WriteHeader(http.StatusInternalServerError)
+				}
+			}()
+			next.ServeHTTP(rw, r)
+		})
+	}
+}
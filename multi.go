@@ -0,0 +1,70 @@
+package slogging
+
+import (
+	"io"
+	"log/slog"
+	"net/http"
+)
+
+// destination for CreateMulti
+type Destination struct {
+	Writer io.Writer
+	// JSON or text output
+	JSON bool
+	// minimum level of the destination. Default INFO
+	Level     slog.Leveler
+	AddSource bool
+}
+
+// create logger writing to multiple destinations, each with its own format and level,
+// e.g. DEBUG and above as JSON to a file and WARN and above as text to stderr.
+//
+// The returned http.Handler (as from Create) controls a shared floor level,
+// initially the minimum of the destination levels. A record is written to a
+// destination if its level is at or above both the floor and the destination level.
+// So raising the floor (e.g. to WARN) silences the more verbose destinations,
+// while lowering it below a destination's level has no effect on that destination.
+func CreateMulti(dests []Destination, attrs ...slog.Attr) (*slog.Logger, http.Handler) {
+	levels := make([]slog.Level, len(dests))
+	for i, d := range dests {
+		levels[i] = slog.LevelInfo
+		if d.Level != nil {
+			levels[i] = d.Level.Level()
+		}
+	}
+	floor := slog.LevelInfo
+	for i, lvl := range levels {
+		if i == 0 || lvl < floor {
+			floor = lvl
+		}
+	}
+
+	v := &slog.LevelVar{}
+	v.Set(floor)
+
+	handlers := make([]slog.Handler, len(dests))
+	for i, d := range dests {
+		o := &slog.HandlerOptions{
+			Level:       maxLevel{floor: v, level: levels[i]},
+			AddSource:   d.AddSource,
+			ReplaceAttr: NamedLevels(nil)}
+		if d.JSON {
+			handlers[i] = slog.NewJSONHandler(d.Writer, o)
+		} else {
+			handlers[i] = slog.NewTextHandler(d.Writer, o)
+		}
+	}
+
+	logger := slog.New(NewFanout(handlers...).WithAttrs(attrs))
+	return logger, logHandler{init: floor, current: v, audit: &levelAudit{log: logger}}
+}
+
+// the higher of floor and level
+type maxLevel struct {
+	floor slog.Leveler
+	level slog.Level
+}
+
+func (m maxLevel) Level() slog.Level {
+	return max(m.floor.Level(), m.level)
+}
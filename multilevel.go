@@ -0,0 +1,162 @@
+package slogging
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// MultiLevelHandler is an http.Handler controlling the levels of multiple
+// named loggers. Like the level handler from Create, it must be mapped to a
+// path prefix of one part, e.g. /log:
+//
+//	GET /log: list names with current and default levels as JSON (of the loggers the request may read)
+//	GET /log/{name}: level of one logger, as the single-logger handler
+//	PUT or POST /log/{name}/{level}: set level of one logger
+//	DELETE /log/{name}, or PUT or POST /log/{name}/reset: reset level of one logger to its initial level
+//
+// Requests for one logger are authorized as by its own handler (see WithLevelAuth),
+// and unknown loggers give 404 Not Found.
+// Register loggers with Register or the WithName option
+type MultiLevelHandler struct {
+	mu       sync.RWMutex
+	handlers map[string]logHandler
+}
+
+func NewMultiLevelHandler() *MultiLevelHandler {
+	return &MultiLevelHandler{handlers: make(map[string]logHandler)}
+}
+
+// register the level handler h (as returned by Create) with name
+func (m *MultiLevelHandler) Register(name string, h http.Handler) error {
+	lh, ok := h.(logHandler)
+	if !ok {
+		return errors.New("handler not created by this package")
+	}
+	if err := validateName(name); err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, exists := m.handlers[name]; exists {
+		return fmt.Errorf("name %q already registered", name)
+	}
+	m.handlers[name] = lh
+	return nil
+}
+
+// register h with name, replacing any handler registered with name
+func (m *MultiLevelHandler) set(name string, h logHandler) error {
+	if err := validateName(name); err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.handlers[name] = h
+	return nil
+}
+
+func validateName(name string) error {
+	if name == "" || strings.Contains(name, "/") {
+		return fmt.Errorf("invalid name %q", name)
+	}
+	return nil
+}
+
+func (m *MultiLevelHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	xs := pathSegments(r.URL.Path)
+	last := xs[len(xs)-1]
+
+	switch r.Method {
+	case http.MethodGet:
+		if h, ok := m.get(last); ok {
+			h.ServeHTTP(w, r)
+			return
+		}
+		if len(xs) >= 2 {
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprintf(w, "unknown logger %q", last)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(m.list(r))
+
+	case http.MethodPut, http.MethodPost:
+		if len(xs) < 2 {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte("specify name and log level as last parts of the URL, e.g. PUT /log/db/debug"))
+			return
+		}
+		h, ok := m.get(xs[len(xs)-2])
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprintf(w, "unknown logger %q", xs[len(xs)-2])
+			return
+		}
+		if !h.authorized(r) {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		if isResetPath(last) {
+			prev := h.resetLevel(r)
+			writeLevelChange(w, r, prev, h.init)
+			return
+		}
+		lvl, err := parseLevelName(last)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(err.Error()))
+			return
+		}
+		prev := h.setLevel(r, lvl)
+		writeLevelChange(w, r, prev, lvl)
+
+	case http.MethodDelete:
+		h, ok := m.get(last)
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprintf(w, "unknown logger %q", last)
+			return
+		}
+		if !h.authorized(r) {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		prev := h.resetLevel(r)
+		writeLevelChange(w, r, prev, h.init)
+
+	case http.MethodOptions:
+		w.Header().Set("Allow", allowedMethods)
+		w.WriteHeader(http.StatusNoContent)
+
+	default:
+		w.Header().Set("Allow", allowedMethods)
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func (m *MultiLevelHandler) get(name string) (logHandler, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	h, ok := m.handlers[name]
+	return h, ok
+}
+
+// levels of the loggers r is authorized to read
+func (m *MultiLevelHandler) list(r *http.Request) map[string]levelResponse {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	xs := make(map[string]levelResponse, len(m.handlers))
+	for name, h := range m.handlers {
+		if h.authorized(r) {
+			xs[name] = h.levelResponse()
+		}
+	}
+	return xs
+}
@@ -0,0 +1,102 @@
+package slogging
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestMultiLevelHandlerAuthorization(t *testing.T) {
+	m := NewMultiLevelHandler()
+	deny := func(*http.Request) bool { return false }
+	log, _ := CreateWithOptions(slog.HandlerOptions{Level: slog.LevelInfo}, false,
+		WithWriter(io.Discard), WithLevelAuth(deny, false), WithName(m, "db"))
+
+	for _, tc := range []struct {
+		method, path string
+		expected     int
+	}{
+		{http.MethodPut, "/log/db/debug", http.StatusForbidden},
+		{http.MethodPost, "/log/db/reset", http.StatusForbidden},
+		{http.MethodDelete, "/log/db", http.StatusForbidden},
+		{http.MethodGet, "/log/db", http.StatusOK},
+		{http.MethodGet, "/log", http.StatusOK},
+	} {
+		w := httptest.NewRecorder()
+		m.ServeHTTP(w, httptest.NewRequest(tc.method, tc.path, nil))
+		if w.Code != tc.expected {
+			t.Errorf("%s %s: expected status %d, got %d", tc.method, tc.path, tc.expected, w.Code)
+		}
+	}
+	if log.Enabled(context.Background(), slog.LevelDebug) {
+		t.Error("expected level to be unchanged")
+	}
+}
+
+func TestWithNameReplacesRegistered(t *testing.T) {
+	m := NewMultiLevelHandler()
+	opts := slog.HandlerOptions{Level: slog.LevelInfo}
+	_, _ = CreateWithOptions(opts, false, WithWriter(io.Discard), WithName(m, "db"))
+	log, _ := CreateWithOptions(opts, false, WithWriter(io.Discard), WithName(m, "db"))
+
+	w := httptest.NewRecorder()
+	m.ServeHTTP(w, httptest.NewRequest(http.MethodPut, "/log/db/debug", nil))
+	if w.Code != http.StatusAccepted {
+		t.Fatalf("expected status %d, got %d", http.StatusAccepted, w.Code)
+	}
+	if !log.Enabled(context.Background(), slog.LevelDebug) {
+		t.Error("expected the level of the last logger registered to be set")
+	}
+}
+
+func TestWithNameInvalid(t *testing.T) {
+	m := NewMultiLevelHandler()
+	var errs []error
+	_, _ = CreateWithOptions(slog.HandlerOptions{Level: slog.LevelInfo}, false, WithWriter(io.Discard),
+		WithErrorHandler(func(err error) { errs = append(errs, err) }), WithName(m, "a/b"))
+
+	if len(errs) != 1 {
+		t.Fatalf("expected 1 error, got %v", errs)
+	}
+	if len(m.list(httptest.NewRequest(http.MethodGet, "/log", nil))) != 0 {
+		t.Errorf("expected no loggers registered, got %v", m.handlers)
+	}
+}
+
+func TestMultiLevelHandlerGet(t *testing.T) {
+	m := NewMultiLevelHandler()
+	deny := func(*http.Request) bool { return false }
+	opts := slog.HandlerOptions{Level: slog.LevelInfo}
+	_, _ = CreateWithOptions(opts, false, WithWriter(io.Discard), WithLevelAuth(deny, true), WithName(m, "db"))
+	_, _ = CreateWithOptions(opts, false, WithWriter(io.Discard), WithName(m, "api"))
+
+	w := httptest.NewRecorder()
+	m.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/log", nil))
+	var levels map[string]levelResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &levels); err != nil {
+		t.Fatalf("invalid JSON %q: %v", w.Body, err)
+	}
+	if _, ok := levels["db"]; ok || len(levels) != 1 || levels["api"].Level != "INFO" {
+		t.Errorf("expected only the authorized logger listed, got %v", levels)
+	}
+
+	for _, tc := range []struct {
+		path     string
+		expected int
+	}{
+		{"/log/db", http.StatusForbidden},
+		{"/log/api", http.StatusOK},
+		{"/log/unknown", http.StatusNotFound},
+		{"/log/", http.StatusOK},
+	} {
+		w := httptest.NewRecorder()
+		m.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tc.path, nil))
+		if w.Code != tc.expected {
+			t.Errorf("GET %s: expected status %d, got %d", tc.path, tc.expected, w.Code)
+		}
+	}
+}
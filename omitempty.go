@@ -0,0 +1,155 @@
+package slogging
+
+import (
+	"context"
+	"log/slog"
+	"reflect"
+)
+
+// kinds of empty values dropped by OmitEmpty, combined with |
+type EmptyKinds int
+
+const (
+	// nil, and nil pointers, maps, slices, funcs, channels and interfaces
+	OmitNil EmptyKinds = 1 << iota
+	// the empty string
+	OmitEmptyString
+	// the zero time.Time
+	OmitZeroTime
+	// zero durations
+	OmitZeroDuration
+	// zero integers and floats
+	OmitZeroNumber
+	// false
+	OmitFalse
+
+	// used by WithOmitEmpty. Booleans and numbers are kept, as false and 0
+	// are usually meaningful
+	DefaultOmitEmpty = OmitNil | OmitEmptyString | OmitZeroTime
+)
+
+// returns a ReplaceAttr func for slog.HandlerOptions, which drops attributes
+// (at any group depth) with empty values of kinds. Values are checked after
+// resolving a slog.LogValuer. The built-in attributes (time, level, msg and
+// source) are never dropped, e.g. an empty message.
+// Note that slog.JSONHandler writes invalid JSON when ReplaceAttr drops all
+// attributes of an inline group, e.g. slog.Group("", "x", ""). WithOmitEmpty
+// does not have this problem, as it drops attributes before the handler does
+// and omits groups left empty
+func OmitEmpty(kinds EmptyKinds) func(groups []string, a slog.Attr) slog.Attr {
+	return func(groups []string, a slog.Attr) slog.Attr {
+		if len(groups) == 0 {
+			switch a.Key {
+			case slog.TimeKey, slog.LevelKey, slog.MessageKey, slog.SourceKey:
+				return a
+			}
+		}
+		if isEmptyValue(a.Value.Resolve(), kinds) {
+			return slog.Attr{}
+		}
+		return a
+	}
+}
+
+func isEmptyValue(v slog.Value, kinds EmptyKinds) bool {
+	switch v.Kind() {
+	case slog.KindString:
+		return kinds&OmitEmptyString != 0 && v.String() == ""
+	case slog.KindTime:
+		return kinds&OmitZeroTime != 0 && v.Time().IsZero()
+	case slog.KindDuration:
+		return kinds&OmitZeroDuration != 0 && v.Duration() == 0
+	case slog.KindInt64:
+		return kinds&OmitZeroNumber != 0 && v.Int64() == 0
+	case slog.KindUint64:
+		return kinds&OmitZeroNumber != 0 && v.Uint64() == 0
+	case slog.KindFloat64:
+		return kinds&OmitZeroNumber != 0 && v.Float64() == 0
+	case slog.KindBool:
+		return kinds&OmitFalse != 0 && !v.Bool()
+	case slog.KindAny:
+		if kinds&OmitNil == 0 {
+			return false
+		}
+		x := v.Any()
+		if x == nil {
+			return true
+		}
+		switch rv := reflect.ValueOf(x); rv.Kind() {
+		case reflect.Pointer, reflect.Map, reflect.Slice, reflect.Func, reflect.Chan, reflect.Interface:
+			return rv.IsNil()
+		}
+	}
+	return false
+}
+
+// drops attributes with empty values of kinds, and groups left without
+// attributes, before passing records to the next handler, see WithOmitEmptyKinds
+type omitEmptyHandler struct {
+	next  slog.Handler
+	kinds EmptyKinds
+}
+
+func (h *omitEmptyHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+func (h *omitEmptyHandler) Handle(ctx context.Context, r slog.Record) error {
+	attrs := recordAttrs(r)
+	if !h.hasEmpty(attrs) {
+		return h.next.Handle(ctx, r)
+	}
+	nr := slog.NewRecord(r.Time, r.Level, r.Message, r.PC)
+	nr.AddAttrs(h.omit(attrs)...)
+	return h.next.Handle(ctx, nr)
+}
+
+func (h *omitEmptyHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	if h.hasEmpty(attrs) {
+		attrs = h.omit(attrs)
+	}
+	if len(attrs) == 0 {
+		return h
+	}
+	return &omitEmptyHandler{next: h.next.WithAttrs(attrs), kinds: h.kinds}
+}
+
+func (h *omitEmptyHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	return &omitEmptyHandler{next: h.next.WithGroup(name), kinds: h.kinds}
+}
+
+func (h *omitEmptyHandler) hasEmpty(attrs []slog.Attr) bool {
+	for _, a := range attrs {
+		v := a.Value.Resolve()
+		if v.Kind() == slog.KindGroup {
+			if len(v.Group()) == 0 || h.hasEmpty(v.Group()) {
+				return true
+			}
+		} else if isEmptyValue(v, h.kinds) {
+			return true
+		}
+	}
+	return false
+}
+
+// attrs without the empty values and groups
+func (h *omitEmptyHandler) omit(attrs []slog.Attr) []slog.Attr {
+	xs := make([]slog.Attr, 0, len(attrs))
+	for _, a := range attrs {
+		a.Value = a.Value.Resolve()
+		if a.Value.Kind() == slog.KindGroup {
+			group := h.omit(a.Value.Group())
+			if len(group) == 0 {
+				continue
+			}
+			a.Value = slog.GroupValue(group...)
+		} else if isEmptyValue(a.Value, h.kinds) {
+			continue
+		}
+		xs = append(xs, a)
+	}
+	return xs
+}
@@ -0,0 +1,46 @@
+package slogging
+
+import (
+	"context"
+	"hash/fnv"
+	"log/slog"
+	"sync"
+)
+
+var (
+	onceMu   sync.Mutex
+	onceSeen = make(map[uint64]struct{})
+)
+
+// log only the first time a level and message is logged (with LogOnce) in the process,
+// e.g. for deprecation warnings. The args are not part of the identity
+func LogOnce(log *slog.Logger, level slog.Level, msg string, args ...any) {
+	if !log.Enabled(context.Background(), level) || !firstTime(level, msg) {
+		return
+	}
+	// skip runtime.Callers, logSkip and LogOnce
+	logSkip(context.Background(), log, level, 3, msg, args...)
+}
+
+// forget what LogOnce has logged, e.g. between tests
+func ResetOnce() {
+	onceMu.Lock()
+	defer onceMu.Unlock()
+	onceSeen = make(map[uint64]struct{})
+}
+
+func firstTime(level slog.Level, msg string) bool {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(level.String()))
+	_, _ = h.Write([]byte{0})
+	_, _ = h.Write([]byte(msg))
+	key := h.Sum64()
+
+	onceMu.Lock()
+	defer onceMu.Unlock()
+	if _, seen := onceSeen[key]; seen {
+		return false
+	}
+	onceSeen[key] = struct{}{}
+	return true
+}
@@ -0,0 +1,415 @@
+package slogging
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"reflect"
+	"time"
+)
+
+// option for CreateWithOptions
+type Option func(*config)
+
+type config struct {
+	writer       io.Writer
+	attrs        []slog.Attr
+	authorize    func(*http.Request) bool
+	authorizeGet bool
+	corsOrigins  []string
+	// register the level handler with name in registry, if not nil
+	registry *MultiLevelHandler
+	name     string
+	// for text output
+	color ColorMode
+	// for JSON output
+	prettyJSON   bool
+	validateJSON bool
+	// called with errors from the handler
+	onError func(error)
+	// see WithFixedFieldOrder
+	fixedOrder bool
+	// add the source with sourceHandler, instead of AddSource, if not nil
+	sourceLevel *slog.Level
+	// see WithDropOnBackpressure
+	dropTimeout time.Duration
+	dropStats   *BackpressureStats
+	// applied in order after the user ReplaceAttr
+	replaceAttrs []func([]string, slog.Attr) slog.Attr
+	// applied in order to the handler, before attrs are attached
+	wrappers []func(slog.Handler) slog.Handler
+}
+
+func newConfig(options []Option) config {
+	cfg := config{writer: os.Stderr, color: ColorOff}
+	for _, opt := range options {
+		opt(&cfg)
+	}
+	return cfg
+}
+
+// compose user ReplaceAttr with those from options.
+// The user func is applied first, so it sees the original values
+func (c config) replaceAttr(user func([]string, slog.Attr) slog.Attr) func([]string, slog.Attr) slog.Attr {
+	fs := []func([]string, slog.Attr) slog.Attr{user}
+	fs = append(fs, c.replaceAttrs...)
+	fs = append(fs, NamedLevels(nil), renderSource)
+	return ChainReplaceAttr(fs...)
+}
+
+// wrap the handler with f
+func withWrapper(f func(slog.Handler) slog.Handler) Option {
+	return func(c *config) {
+		c.wrappers = append(c.wrappers, f)
+	}
+}
+
+// write log records to w. Default is os.Stderr
+func WithWriter(w io.Writer) Option {
+	return func(c *config) {
+		c.writer = w
+	}
+}
+
+// attach attributes to every record. May be specified multiple times
+func WithAttrs(attrs ...slog.Attr) Option {
+	return func(c *config) {
+		c.attrs = append(c.attrs, attrs...)
+	}
+}
+
+// require authorize to return true for requests to the level http.Handler,
+// otherwise it responds 403 Forbidden and leaves the level unchanged.
+// GET requests (reading the level) are always allowed, unless includeGet is true.
+// Default is no authorization.
+func WithLevelAuth(authorize func(*http.Request) bool, includeGet bool) Option {
+	return func(c *config) {
+		c.authorize = authorize
+		c.authorizeGet = includeGet
+	}
+}
+
+// wrap the handler in a ContextHandler, adding registered context values
+// (see RegisterContextValue and RegisterContextFunc) to each record
+func WithContextHandler() Option {
+	return func(c *config) {
+		c.wrappers = append(c.wrappers, func(h slog.Handler) slog.Handler {
+			return NewContextHandler(h)
+		})
+	}
+}
+
+// format the time of each record with layout (see TimeFormat).
+// Use layout NoTime to drop the time attribute
+func WithTimeFormat(layout string, loc *time.Location) Option {
+	return func(c *config) {
+		c.replaceAttrs = append(c.replaceAttrs, TimeFormat(layout, loc))
+	}
+}
+
+// drop the time attribute, for both JSON and text output, e.g. when journald
+// or the container runtime already timestamps each line.
+// Same as WithTimeFormat(NoTime, nil)
+func WithoutTime() Option {
+	return WithTimeFormat(NoTime, nil)
+}
+
+// redact attributes with any of keys (see Redact).
+// Like other ReplaceAttr options, it is applied after the user ReplaceAttr
+// in the order options are given, so the user func sees the original value
+func WithRedact(keys ...string) Option {
+	return func(c *config) {
+		c.replaceAttrs = append(c.replaceAttrs, Redact(keys...))
+	}
+}
+
+// redact attributes for which match returns true (see RedactFunc)
+func WithRedactFunc(match func(groups []string, a slog.Attr) bool) Option {
+	return func(c *config) {
+		c.replaceAttrs = append(c.replaceAttrs, RedactFunc(match))
+	}
+}
+
+// pass at most the first n records with the same level and message
+// per interval (see SamplingHandler)
+func WithSampling(n int, interval time.Duration) Option {
+	return func(c *config) {
+		c.wrappers = append(c.wrappers, func(h slog.Handler) slog.Handler {
+			return NewSamplingHandler(h, n, interval)
+		})
+	}
+}
+
+// use colored ConsoleHandler for text output when mode is enabled,
+// otherwise the standard text handler. JSON output is unaffected.
+// Default is ColorOff
+func WithColor(mode ColorMode) Option {
+	return func(c *config) {
+		c.color = mode
+	}
+}
+
+// register the level handler with name in m, replacing any handler already
+// registered with name, e.g. when a logger is created again.
+// If the name is invalid, the handler is not registered and the error is
+// passed to the func given with WithErrorHandler, if any
+func WithName(m *MultiLevelHandler, name string) Option {
+	return func(c *config) {
+		c.registry = m
+		c.name = name
+	}
+}
+
+// redact values of any of types or implementing Secret (see RedactTypes)
+func WithRedactTypes(types ...reflect.Type) Option {
+	return func(c *config) {
+		c.replaceAttrs = append(c.replaceAttrs, RedactTypes(types...))
+	}
+}
+
+// collapse attributes with duplicate keys, keeping the first (DedupKeepFirst)
+// or last (DedupKeepLast), see DedupHandler.
+// Handler options are applied in order, so give it before WithContextHandler
+// to also collapse attributes from the context
+func WithDedupAttrs(keep string) Option {
+	return withWrapper(func(h slog.Handler) slog.Handler {
+		return NewDedupHandler(h, keep)
+	})
+}
+
+// add CORS headers to responses from the level http.Handler, for requests
+// with an Origin header exactly matching one of allowedOrigins,
+// e.g. "https://dashboard.example.com". Preflight OPTIONS requests are
+// answered without authorization (see WithLevelAuth).
+// Default is no CORS headers
+func WithCORS(allowedOrigins ...string) Option {
+	return func(c *config) {
+		c.corsOrigins = append(c.corsOrigins, allowedOrigins...)
+	}
+}
+
+// count records by level in counts (see MetricsHandler)
+func WithMetrics(counts *LevelCounts) Option {
+	return withWrapper(func(h slog.Handler) slog.Handler {
+		return NewMetricsHandler(h, counts)
+	})
+}
+
+// indent JSON output for reading locally, when enabled.
+// Each record is still one JSON document, but spans multiple lines.
+// It is slower, so should not be enabled in production.
+// Text output is unaffected
+func WithPrettyJSON(enabled bool) Option {
+	return func(c *config) {
+		c.prettyJSON = enabled
+	}
+}
+
+// call onError with errors returned by the handler, e.g. when writing to a broken pipe.
+// The slog.Logger discards these errors, so by default they are silently ignored.
+// onError is called synchronously, so should be fast, e.g. incrementing a metric
+func WithErrorHandler(onError func(err error)) Option {
+	return func(c *config) {
+		c.onError = onError
+	}
+}
+
+// shorten the source file (with AddSource) as specified by mode, see SourceTrim
+func WithSourceTrim(mode SourceTrimMode) Option {
+	return func(c *config) {
+		c.replaceAttrs = append(c.replaceAttrs, SourceTrim(mode))
+	}
+}
+
+// truncate attribute values longer than n bytes (see MaxAttrLen).
+// n <= 0 means DefaultMaxAttrLen
+func WithMaxAttrLen(n int) Option {
+	return func(c *config) {
+		c.replaceAttrs = append(c.replaceAttrs, MaxAttrLen(n))
+	}
+}
+
+// throttle records per value of the attribute key (see RateLimitByAttr)
+func WithRateLimitByAttr(key string, rate, burst int) Option {
+	return withWrapper(func(h slog.Handler) slog.Handler {
+		return RateLimitByAttr(h, key, rate, burst)
+	})
+}
+
+// add a sequence number attribute with key to every record (see SequenceHandler).
+// Empty key means SequenceKey
+func WithSequence(key string) Option {
+	return withWrapper(func(h slog.Handler) slog.Handler {
+		return NewSequenceHandler(h, key)
+	})
+}
+
+// add the fully-qualified name of the calling function, e.g.
+// "github.com/org/app/server.(*Server).Start", as the attribute CallerFuncKey,
+// when enabled. It is taken from the same program counter as the source
+// with AddSource, so it is the caller also for the helpers of this package
+// (e.g. Infof and Fatal). Records without a program counter are left unchanged
+func WithCallerFunc(enabled bool) Option {
+	return func(c *config) {
+		if enabled {
+			c.wrappers = append(c.wrappers, func(h slog.Handler) slog.Handler {
+				return &callerFuncHandler{next: h}
+			})
+		}
+	}
+}
+
+// check that each JSON record is valid before it is written, when enabled.
+// An invalid record (e.g. from a faulty ReplaceAttr or MarshalJSON) is replaced
+// by an ERROR record with the message "invalid JSON log record" and the invalid
+// record as the string attribute "invalidRecord", and is counted in
+// InvalidJSONRecords. It costs a parse of every record, so is meant for
+// development and tests. Text output is unaffected
+func WithValidateJSON(enabled bool) Option {
+	return func(c *config) {
+		c.validateJSON = enabled
+	}
+}
+
+// sort attributes alphabetically by key within each group, when enabled, so
+// output does not depend on the order attributes were added, e.g. from maps.
+// Applies to both text and JSON output, and to attributes from With.
+// It costs rebuilding and sorting the attributes of each record, and
+// attributes from With are no longer formatted once in advance
+func WithSortedKeys(enabled bool) Option {
+	return func(c *config) {
+		if enabled {
+			c.wrappers = append(c.wrappers, func(h slog.Handler) slog.Handler {
+				return &sortedKeysHandler{next: h}
+			})
+		}
+	}
+}
+
+// rename the built-in attributes and map levels for Google Cloud Logging
+// (see GCPFieldNames). Give it after options such as WithTimeFormat which
+// match the built-in keys
+func WithGCPFieldNames() Option {
+	return WithFieldNames(FieldNamesGCP)
+}
+
+// rename the built-in attributes for AWS CloudWatch Logs (see AWSFieldNames).
+// Give it after options such as WithTimeFormat which match the built-in keys
+func WithAWSFieldNames() Option {
+	return WithFieldNames(FieldNamesAWS)
+}
+
+// rename the built-in attributes for a platform (see FieldNames), e.g.
+// WithFieldNames(FieldNamesGCP). Give it after options such as WithTimeFormat
+// which match the built-in keys
+func WithFieldNames(preset FieldNamePreset) Option {
+	return func(c *config) {
+		if f := FieldNames(preset); f != nil {
+			c.replaceAttrs = append(c.replaceAttrs, f)
+		}
+	}
+}
+
+// drop attributes with nil, empty string and zero time values, when enabled
+// (see DefaultOmitEmpty)
+func WithOmitEmpty(enabled bool) Option {
+	if !enabled {
+		return func(*config) {}
+	}
+	return WithOmitEmptyKinds(DefaultOmitEmpty)
+}
+
+// drop attributes with empty values of kinds, at any group depth, and groups
+// left without attributes. The rules are as for OmitEmpty, but applied to
+// the attributes before the handler, as they are given to the logger.
+// Handler options are applied in order, so give it before WithContextHandler
+// to also drop attributes from the context
+func WithOmitEmptyKinds(kinds EmptyKinds) Option {
+	return withWrapper(func(h slog.Handler) slog.Handler {
+		return &omitEmptyHandler{next: h, kinds: kinds}
+	})
+}
+
+// keep at most n attributes per record, including those from With, e.g. to
+// guard against a loop adding attributes with With. Attributes in groups are
+// counted individually, while the groups are not, and the first n are kept.
+// When attributes are dropped, an attribute AttrsTruncatedKey with the number
+// dropped is added with the record's attributes (in the current group, if any).
+// n <= 0 means no limit
+func WithMaxAttrs(n int) Option {
+	return func(c *config) {
+		if n > 0 {
+			c.wrappers = append(c.wrappers, func(h slog.Handler) slog.Handler {
+				return &maxAttrsHandler{next: h, max: n}
+			})
+		}
+	}
+}
+
+// nest groups at most n deep (n > 0), guarding against unbounded nesting,
+// e.g. by a library calling WithGroup in a loop. Groups beyond are flattened
+// to attributes with dotted keys (e.g. "a.b.c") and the attribute
+// GroupsFlattenedKey=true is added to the innermost group kept
+func WithMaxGroupDepth(n int) Option {
+	return func(c *config) {
+		if n > 0 {
+			c.wrappers = append(c.wrappers, func(h slog.Handler) slog.Handler {
+				return &groupDepthHandler{next: h, max: n}
+			})
+		}
+	}
+}
+
+// only log records for which keep returns true (see FilterHandler)
+func WithFilter(keep func(ctx context.Context, r slog.Record) bool) Option {
+	return withWrapper(func(h slog.Handler) slog.Handler {
+		return NewFilterHandler(h, keep)
+	})
+}
+
+// write records from a background goroutine, and drop records (counting them
+// in stats, which may be nil) when they can not be queued within timeout
+// because the writer is slow, e.g. a stderr consumer not keeping up.
+// Logging then never blocks a caller for longer than timeout, at the price
+// of losing records, so use it for latency-sensitive services only.
+// As records are written later, write errors are only reported to
+// WithErrorHandler. The writer is registered for the package Flush, and its
+// goroutine is stopped by the package Close
+func WithDropOnBackpressure(timeout time.Duration, stats *BackpressureStats) Option {
+	return func(c *config) {
+		c.dropTimeout = timeout
+		c.dropStats = stats
+	}
+}
+
+// write the fields in a fixed order: time, level and msg, followed by the
+// attributes, also when renamed by ReplaceAttr (e.g. WithGCPFieldNames).
+// The slog handlers write the source (with AddSource) between the level and
+// the message, so it is written as the first attribute instead
+func WithFixedFieldOrder() Option {
+	return func(c *config) {
+		c.fixedOrder = true
+	}
+}
+
+// add the source only to records at or above threshold, e.g. slog.LevelWarn,
+// regardless of AddSource, saving the output and the cost of resolving the
+// source for the other records.
+// The source is that of the caller, as with AddSource, but written as the
+// first attribute after the message
+func WithSourceAboveLevel(threshold slog.Level) Option {
+	return func(c *config) {
+		c.sourceLevel = &threshold
+	}
+}
+
+// drop records beyond the log budget of their context (see ContextWithLogBudget
+// and LogBudget), e.g. so a single pathological request can not log without bound.
+// Records logged without a budget in the context are unaffected
+func WithLogBudget() Option {
+	return withWrapper(func(h slog.Handler) slog.Handler {
+		return &budgetHandler{next: h}
+	})
+}
@@ -0,0 +1,25 @@
+package slogging
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+)
+
+// re-encodes each written JSON record with indentation.
+// Writes that are not valid JSON are passed through unchanged
+type prettyJSONWriter struct {
+	w io.Writer
+}
+
+func (p *prettyJSONWriter) Write(b []byte) (int, error) {
+	var buf bytes.Buffer
+	if err := json.Indent(&buf, bytes.TrimSpace(b), "", "  "); err != nil {
+		return p.w.Write(b)
+	}
+	buf.WriteByte('\n')
+	if _, err := p.w.Write(buf.Bytes()); err != nil {
+		return 0, err
+	}
+	return len(b), nil
+}
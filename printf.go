@@ -0,0 +1,78 @@
+package slogging
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"runtime"
+	"time"
+)
+
+// printf-style helpers. Structured logging with attributes is still preferred,
+// as attributes can be queried, where a formatted message can not.
+// The source (with AddSource) is the caller of the helper.
+
+func Debugf(log *slog.Logger, format string, args ...any) {
+	logf(context.Background(), log, slog.LevelDebug, format, args...)
+}
+
+func Infof(log *slog.Logger, format string, args ...any) {
+	logf(context.Background(), log, slog.LevelInfo, format, args...)
+}
+
+func Warnf(log *slog.Logger, format string, args ...any) {
+	logf(context.Background(), log, slog.LevelWarn, format, args...)
+}
+
+func Errorf(log *slog.Logger, format string, args ...any) {
+	logf(context.Background(), log, slog.LevelError, format, args...)
+}
+
+func DebugfContext(ctx context.Context, log *slog.Logger, format string, args ...any) {
+	logf(ctx, log, slog.LevelDebug, format, args...)
+}
+
+func InfofContext(ctx context.Context, log *slog.Logger, format string, args ...any) {
+	logf(ctx, log, slog.LevelInfo, format, args...)
+}
+
+func WarnfContext(ctx context.Context, log *slog.Logger, format string, args ...any) {
+	logf(ctx, log, slog.LevelWarn, format, args...)
+}
+
+func ErrorfContext(ctx context.Context, log *slog.Logger, format string, args ...any) {
+	logf(ctx, log, slog.LevelError, format, args...)
+}
+
+// log at a level chosen at runtime, e.g. WARN for a retryable error and ERROR
+// otherwise, without switching on the level. Like log.Log, with the source
+// being the caller of LogAt. A nil ctx means context.Background()
+func LogAt(ctx context.Context, log *slog.Logger, level slog.Level, msg string, args ...any) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	// skip runtime.Callers, logSkip and LogAt
+	logSkip(ctx, log, level, 3, msg, args...)
+}
+
+// format and log, if enabled. Must be called directly by the exported helper
+func logf(ctx context.Context, log *slog.Logger, level slog.Level, format string, args ...any) {
+	if !log.Enabled(ctx, level) {
+		return
+	}
+	// skip runtime.Callers, logSkip, logf and the helper
+	logSkip(ctx, log, level, 4, fmt.Sprintf(format, args...))
+}
+
+// log with the source at skip frames up the stack, as counted by runtime.Callers,
+// like the slog.Logger methods do
+func logSkip(ctx context.Context, log *slog.Logger, level slog.Level, skip int, msg string, args ...any) {
+	if !log.Enabled(ctx, level) {
+		return
+	}
+	var pcs [1]uintptr
+	runtime.Callers(skip, pcs[:])
+	r := slog.NewRecord(time.Now(), level, msg, pcs[0])
+	r.Add(args...)
+	_ = log.Handler().Handle(ctx, r)
+}
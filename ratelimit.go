@@ -0,0 +1,140 @@
+package slogging
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// interval for reporting dropped records and evicting idle buckets
+const rateLimitReportInterval = 10 * time.Second
+
+// RateLimitHandler is a slog.Handler throttling records per value of an attribute
+// (e.g. tenant_id) with a token bucket for each value, so one value can not
+// drown out the others. Records over the limit are dropped, and the number of
+// dropped records per value is logged every 10 seconds (when records are logged).
+// The attribute is read from the record, or from WithAttrs (before any group).
+// Records without the attribute are not throttled.
+// Idle buckets are evicted.
+type RateLimitHandler struct {
+	next slog.Handler
+	l    *rateLimiter
+	// value of the attribute from WithAttrs
+	value    string
+	hasValue bool
+	grouped  bool
+}
+
+type rateLimiter struct {
+	root  slog.Handler
+	key   string
+	rate  float64
+	burst float64
+
+	mu         sync.Mutex
+	buckets    map[string]*tokenBucket
+	lastReport time.Time
+}
+
+type tokenBucket struct {
+	tokens  float64
+	last    time.Time
+	dropped int
+}
+
+// create RateLimitHandler allowing rate records per second, with bursts of up to
+// burst records, for each value of the attribute key
+func RateLimitByAttr(next slog.Handler, key string, rate, burst int) *RateLimitHandler {
+	return &RateLimitHandler{
+		next: next,
+		l: &rateLimiter{
+			root:       next,
+			key:        key,
+			rate:       float64(rate),
+			burst:      float64(max(burst, 1)),
+			buckets:    make(map[string]*tokenBucket),
+			lastReport: time.Now()}}
+}
+
+func (h *RateLimitHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+func (h *RateLimitHandler) Handle(ctx context.Context, r slog.Record) error {
+	value, ok := h.value, h.hasValue
+	r.Attrs(func(a slog.Attr) bool {
+		if a.Key == h.l.key {
+			value, ok = a.Value.Resolve().String(), true
+			return false
+		}
+		return true
+	})
+	if !ok {
+		return h.next.Handle(ctx, r)
+	}
+
+	allowed, reports := h.l.allow(value, time.Now())
+	for _, x := range reports {
+		_ = h.l.root.Handle(ctx, x)
+	}
+	if !allowed {
+		return nil
+	}
+	return h.next.Handle(ctx, r)
+}
+
+func (h *RateLimitHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	h2 := *h
+	h2.next = h.next.WithAttrs(attrs)
+	if !h.grouped {
+		for _, a := range attrs {
+			if a.Key == h.l.key {
+				h2.value, h2.hasValue = a.Value.Resolve().String(), true
+			}
+		}
+	}
+	return &h2
+}
+
+func (h *RateLimitHandler) WithGroup(name string) slog.Handler {
+	h2 := *h
+	h2.next = h.next.WithGroup(name)
+	h2.grouped = h.grouped || name != ""
+	return &h2
+}
+
+// whether a record for value is allowed, and records reporting dropped records
+func (l *rateLimiter) allow(value string, now time.Time) (bool, []slog.Record) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	var reports []slog.Record
+	if now.Sub(l.lastReport) >= rateLimitReportInterval {
+		for v, b := range l.buckets {
+			if b.dropped > 0 {
+				r := slog.NewRecord(now, slog.LevelWarn, "rate limited records dropped", 0)
+				r.AddAttrs(slog.String(l.key, v), slog.Int("count", b.dropped))
+				reports = append(reports, r)
+				b.dropped = 0
+			} else if now.Sub(b.last) >= rateLimitReportInterval {
+				delete(l.buckets, v)
+			}
+		}
+		l.lastReport = now
+	}
+
+	b := l.buckets[value]
+	if b == nil {
+		b = &tokenBucket{tokens: l.burst, last: now}
+		l.buckets[value] = b
+	}
+	b.tokens = min(l.burst, b.tokens+now.Sub(b.last).Seconds()*l.rate)
+	b.last = now
+	if b.tokens < 1 {
+		b.dropped++
+		return false, reports
+	}
+	b.tokens--
+	return true, reports
+}
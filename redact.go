@@ -0,0 +1,67 @@
+package slogging
+
+import (
+	"log/slog"
+	"reflect"
+	"strings"
+)
+
+// value replacing redacted attributes
+const Redacted = "[REDACTED]"
+
+// returns a ReplaceAttr func for slog.HandlerOptions, which replaces the value
+// of attributes with any of keys (case-insensitive, at any group depth) with Redacted
+func Redact(keys ...string) func(groups []string, a slog.Attr) slog.Attr {
+	set := make(map[string]struct{}, len(keys))
+	for _, k := range keys {
+		set[strings.ToLower(k)] = struct{}{}
+	}
+	return RedactFunc(func(_ []string, a slog.Attr) bool {
+		_, ok := set[strings.ToLower(a.Key)]
+		return ok
+	})
+}
+
+// returns a ReplaceAttr func for slog.HandlerOptions, which replaces the value
+// of attributes for which match returns true with Redacted
+func RedactFunc(match func(groups []string, a slog.Attr) bool) func(groups []string, a slog.Attr) slog.Attr {
+	return func(groups []string, a slog.Attr) slog.Attr {
+		if match(groups, a) {
+			return slog.String(a.Key, Redacted)
+		}
+		return a
+	}
+}
+
+// value replacing attributes redacted by type
+const RedactedSecret = "***"
+
+// implemented by values which must never be logged, see RedactTypes
+type Secret interface {
+	LogRedact()
+}
+
+// returns a ReplaceAttr func for slog.HandlerOptions, which replaces values
+// of any of types or implementing Secret with RedactedSecret, regardless of
+// the key and at any group depth.
+// Note that a slog.LogValuer is resolved before ReplaceAttr is called,
+// so it is the type of the resolved value which is matched
+func RedactTypes(types ...reflect.Type) func(groups []string, a slog.Attr) slog.Attr {
+	set := make(map[reflect.Type]struct{}, len(types))
+	for _, t := range types {
+		set[t] = struct{}{}
+	}
+	return func(_ []string, a slog.Attr) slog.Attr {
+		if a.Value.Kind() != slog.KindAny {
+			return a
+		}
+		v := a.Value.Any()
+		if _, ok := v.(Secret); ok {
+			return slog.String(a.Key, RedactedSecret)
+		}
+		if _, ok := set[reflect.TypeOf(v)]; ok {
+			return slog.String(a.Key, RedactedSecret)
+		}
+		return a
+	}
+}
@@ -0,0 +1,158 @@
+package slogging
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"math"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// RingBufferHandler is a slog.Handler keeping the last records in memory,
+// overwriting the oldest when full, e.g. for a "recent logs" view.
+// It records every record it is passed (at any level), so combine it with
+// another handler with NewFanout, or set a level with CreateWithHandler.
+// Attributes and groups from WithAttrs and WithGroup are included in the
+// kept records, and handlers derived from it share the buffer.
+// It is safe for concurrent use.
+//
+// It is also an http.Handler responding to GET with the kept records as JSON,
+// oldest first:
+//
+//	[{"time": "...", "level": "INFO", "msg": "...", "attrs": {"key": "value", "group": {...}}}]
+//
+// With query parameter level (e.g. ?level=warn), only records at or above
+// that level are returned
+type RingBufferHandler struct {
+	b    *ringBuffer
+	goas []groupOrAttrs
+}
+
+type ringBuffer struct {
+	mu      sync.Mutex
+	records []slog.Record
+	// index of the oldest record, when full
+	next int
+}
+
+// create RingBufferHandler keeping the last size records (at least 1)
+func NewRingBufferHandler(size int) *RingBufferHandler {
+	return &RingBufferHandler{b: &ringBuffer{records: make([]slog.Record, 0, max(size, 1))}}
+}
+
+func (h *RingBufferHandler) Enabled(context.Context, slog.Level) bool {
+	return true
+}
+
+func (h *RingBufferHandler) Handle(_ context.Context, r slog.Record) error {
+	nr := slog.NewRecord(r.Time, r.Level, r.Message, r.PC)
+	nr.AddAttrs(nestAttrs(h.goas, recordAttrs(r))...)
+
+	b := h.b
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if len(b.records) < cap(b.records) {
+		b.records = append(b.records, nr)
+		return nil
+	}
+	b.records[b.next] = nr
+	b.next = (b.next + 1) % len(b.records)
+	return nil
+}
+
+func (h *RingBufferHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	if len(attrs) == 0 {
+		return h
+	}
+	return &RingBufferHandler{b: h.b, goas: appendGroupOrAttrs(h.goas, groupOrAttrs{attrs: attrs})}
+}
+
+func (h *RingBufferHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	return &RingBufferHandler{b: h.b, goas: appendGroupOrAttrs(h.goas, groupOrAttrs{group: name})}
+}
+
+// kept records, oldest first
+func (h *RingBufferHandler) Records() []slog.Record {
+	b := h.b
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	xs := make([]slog.Record, 0, len(b.records))
+	for i := range b.records {
+		xs = append(xs, b.records[(b.next+i)%len(b.records)].Clone())
+	}
+	return xs
+}
+
+func (h *RingBufferHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.Header().Set("Allow", http.MethodGet)
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	minLevel := slog.Level(math.MinInt)
+	if s := r.URL.Query().Get("level"); s != "" {
+		lvl, err := parseLevelName(s)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(err.Error()))
+			return
+		}
+		minLevel = lvl
+	}
+
+	resp := []ringBufferRecord{}
+	for _, rec := range h.Records() {
+		if rec.Level < minLevel {
+			continue
+		}
+		x := ringBufferRecord{Time: rec.Time, Level: rec.Level.String(), Message: rec.Message}
+		if rec.NumAttrs() > 0 {
+			x.Attrs = attrsMap(recordAttrs(rec))
+		}
+		resp = append(resp, x)
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(resp)
+}
+
+// JSON response of RingBufferHandler
+type ringBufferRecord struct {
+	Time    time.Time      `json:"time"`
+	Level   string         `json:"level"`
+	Message string         `json:"msg"`
+	Attrs   map[string]any `json:"attrs,omitempty"`
+}
+
+// attributes as a map for JSON encoding, with groups as nested maps.
+// Errors are rendered as their message, which json.Marshal would not
+func attrsMap(attrs []slog.Attr) map[string]any {
+	m := make(map[string]any, len(attrs))
+	for _, a := range attrs {
+		v := a.Value.Resolve()
+		switch v.Kind() {
+		case slog.KindGroup:
+			if a.Key == "" {
+				for k, x := range attrsMap(v.Group()) {
+					m[k] = x
+				}
+			} else if len(v.Group()) > 0 {
+				m[a.Key] = attrsMap(v.Group())
+			}
+		case slog.KindAny:
+			if err, ok := v.Any().(error); ok {
+				m[a.Key] = err.Error()
+			} else {
+				m[a.Key] = v.Any()
+			}
+		default:
+			m[a.Key] = v.Any()
+		}
+	}
+	return m
+}
@@ -0,0 +1,161 @@
+package slogging
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"sync"
+)
+
+// create logger (like Create) writing to the file at path.
+// The file is rotated to path.1, path.2, ... when it would exceed maxSizeBytes,
+// keeping at most maxBackups old files.
+// Close the returned io.Closer on shutdown.
+func CreateToFile(path string, maxSizeBytes int64, maxBackups int, opts slog.HandlerOptions, jsonOutput bool, attrs ...slog.Attr) (*slog.Logger, http.Handler, io.Closer, error) {
+	w, err := NewRotatingWriter(path, maxSizeBytes, maxBackups)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	logger, h := CreateWithWriter(w, opts, jsonOutput, attrs...)
+	return logger, h, w, nil
+}
+
+// RotatingWriter is an io.WriteCloser writing to a file, which is rotated
+// when it exceeds a maximum size.
+// It is safe for concurrent use. Each call to Write is expected to be a
+// complete record (as the slog handlers do) and is never split between files.
+// It is registered for the package Flush until closed.
+type RotatingWriter struct {
+	path       string
+	maxSize    int64
+	maxBackups int
+
+	mu     sync.Mutex
+	file   *os.File
+	size   int64
+	closed bool
+}
+
+// open (or create) the file at path for appending.
+// maxSizeBytes must be positive. maxBackups may be 0 to not keep any old files.
+func NewRotatingWriter(path string, maxSizeBytes int64, maxBackups int) (*RotatingWriter, error) {
+	if maxSizeBytes <= 0 {
+		return nil, errors.New("maxSizeBytes must be positive")
+	}
+	if maxBackups < 0 {
+		return nil, errors.New("maxBackups must not be negative")
+	}
+
+	w := &RotatingWriter{
+		path:       path,
+		maxSize:    maxSizeBytes,
+		maxBackups: maxBackups}
+	if err := w.open(); err != nil {
+		return nil, err
+	}
+	RegisterFlusher(w)
+	return w, nil
+}
+
+func (w *RotatingWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.closed {
+		return 0, os.ErrClosed
+	}
+	if w.size > 0 && w.size+int64(len(p)) > w.maxSize {
+		// if rotation fails, keep writing to the current file rather than
+		// dropping the record
+		if err := w.rotate(); err != nil && w.file == nil {
+			if err := w.open(); err != nil {
+				return 0, err
+			}
+		}
+	}
+	n, err := w.file.Write(p)
+	w.size += int64(n)
+	return n, err
+}
+
+// sync the current file to disk
+func (w *RotatingWriter) Flush() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.file == nil {
+		return nil
+	}
+	return w.file.Sync()
+}
+
+// flush and close the current file. Subsequent writes fail with os.ErrClosed
+func (w *RotatingWriter) Close() error {
+	DeregisterFlusher(w)
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.closed {
+		return nil
+	}
+	w.closed = true
+	if w.file == nil {
+		return nil
+	}
+	err := errors.Join(w.file.Sync(), w.file.Close())
+	w.file = nil
+	return err
+}
+
+func (w *RotatingWriter) open() error {
+	f, err := os.OpenFile(w.path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o644)
+	if err != nil {
+		return err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		_ = f.Close()
+		return err
+	}
+	w.file = f
+	w.size = info.Size()
+	return nil
+}
+
+// must be called with mu held
+func (w *RotatingWriter) rotate() error {
+	if err := w.file.Close(); err != nil {
+		return err
+	}
+	w.file = nil
+
+	if w.maxBackups == 0 {
+		if err := os.Remove(w.path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+		return w.open()
+	}
+
+	// remove the oldest backup and shift the rest by one
+	oldest := backupName(w.path, w.maxBackups)
+	if err := os.Remove(oldest); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	for i := w.maxBackups - 1; i >= 1; i-- {
+		err := os.Rename(backupName(w.path, i), backupName(w.path, i+1))
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+	}
+	if err := os.Rename(w.path, backupName(w.path, 1)); err != nil {
+		return err
+	}
+	return w.open()
+}
+
+func backupName(path string, n int) string {
+	return fmt.Sprintf("%s.%d", path, n)
+}
@@ -0,0 +1,117 @@
+package slogging
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// SamplingHandler is a slog.Handler passing at most the first n records with
+// the same level and message per interval to the next handler.
+// When records have been dropped, a "suppressed messages" record with the
+// message and count is logged on the first record after the interval.
+// Keys not seen within an interval are evicted.
+type SamplingHandler struct {
+	next slog.Handler
+	s    *sampler
+}
+
+// shared by a SamplingHandler and those derived from it
+type sampler struct {
+	// handler for the summary records
+	root     slog.Handler
+	n        int
+	interval time.Duration
+
+	mu        sync.Mutex
+	entries   map[samplingKey]*samplingEntry
+	lastSweep time.Time
+}
+
+type samplingKey struct {
+	level   slog.Level
+	message string
+}
+
+type samplingEntry struct {
+	start      time.Time
+	count      int
+	suppressed int
+}
+
+// create SamplingHandler passing the first n records per interval to next
+func NewSamplingHandler(next slog.Handler, n int, interval time.Duration) *SamplingHandler {
+	return &SamplingHandler{
+		next: next,
+		s: &sampler{
+			root:      next,
+			n:         n,
+			interval:  interval,
+			entries:   make(map[samplingKey]*samplingEntry),
+			lastSweep: time.Now()}}
+}
+
+func (h *SamplingHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+func (h *SamplingHandler) Handle(ctx context.Context, r slog.Record) error {
+	pass, summaries := h.s.allow(samplingKey{level: r.Level, message: r.Message}, time.Now())
+	for _, x := range summaries {
+		_ = h.s.root.Handle(ctx, x)
+	}
+	if !pass {
+		return nil
+	}
+	return h.next.Handle(ctx, r)
+}
+
+func (h *SamplingHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &SamplingHandler{next: h.next.WithAttrs(attrs), s: h.s}
+}
+
+func (h *SamplingHandler) WithGroup(name string) slog.Handler {
+	return &SamplingHandler{next: h.next.WithGroup(name), s: h.s}
+}
+
+// whether to pass a record with key, and summary records to log for keys
+// with suppressed records in an expired interval
+func (s *sampler) allow(key samplingKey, now time.Time) (bool, []slog.Record) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var summaries []slog.Record
+	if now.Sub(s.lastSweep) >= s.interval {
+		for k, e := range s.entries {
+			if now.Sub(e.start) >= s.interval {
+				if e.suppressed > 0 {
+					summaries = append(summaries, suppressedRecord(k, e.suppressed, now))
+				}
+				delete(s.entries, k)
+			}
+		}
+		s.lastSweep = now
+	}
+
+	e := s.entries[key]
+	if e == nil || now.Sub(e.start) >= s.interval {
+		if e != nil && e.suppressed > 0 {
+			summaries = append(summaries, suppressedRecord(key, e.suppressed, now))
+		}
+		e = &samplingEntry{start: now}
+		s.entries[key] = e
+	}
+	e.count++
+	if e.count <= s.n {
+		return true, summaries
+	}
+	e.suppressed++
+	return false, summaries
+}
+
+func suppressedRecord(key samplingKey, count int, now time.Time) slog.Record {
+	r := slog.NewRecord(now, key.level, "suppressed messages", 0)
+	r.AddAttrs(slog.String("message", key.message), slog.Int("count", count))
+	return r
+}
@@ -0,0 +1,81 @@
+package slogging
+
+import (
+	"context"
+	"log/slog"
+	"sync/atomic"
+)
+
+// default attribute key of SequenceHandler
+const SequenceKey = "seq"
+
+// SequenceHandler is a slog.Handler adding an attribute with a sequence number
+// 1, 2, 3, ... to every record, at the top level, before passing it to the
+// next handler. The counter is shared by all handlers derived from it with
+// WithAttrs and WithGroup, so gaps or reordering downstream reveal lost records.
+// Only enabled records are numbered
+type SequenceHandler struct {
+	next    slog.Handler
+	key     string
+	counter *atomic.Uint64
+	goas    []groupOrAttrs
+}
+
+// create SequenceHandler adding the sequence number with key. Empty key means SequenceKey
+func NewSequenceHandler(next slog.Handler, key string) *SequenceHandler {
+	if key == "" {
+		key = SequenceKey
+	}
+	return &SequenceHandler{next: next, key: key, counter: &atomic.Uint64{}}
+}
+
+func (h *SequenceHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+func (h *SequenceHandler) Handle(ctx context.Context, r slog.Record) error {
+	seq := slog.Uint64(h.key, h.counter.Add(1))
+	if len(h.goas) == 0 {
+		r = r.Clone()
+		r.AddAttrs(seq)
+		return h.next.Handle(ctx, r)
+	}
+
+	nr := slog.NewRecord(r.Time, r.Level, r.Message, r.PC)
+	nr.AddAttrs(seq)
+	nr.AddAttrs(nestAttrs(h.goas, recordAttrs(r))...)
+	return h.next.Handle(ctx, nr)
+}
+
+func (h *SequenceHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	if len(attrs) == 0 {
+		return h
+	}
+	h2 := *h
+	if len(h.goas) == 0 {
+		h2.next = h.next.WithAttrs(attrs)
+	} else {
+		h2.goas = appendGroupOrAttrs(h.goas, groupOrAttrs{attrs: attrs})
+	}
+	return &h2
+}
+
+func (h *SequenceHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	h2 := *h
+	h2.goas = appendGroupOrAttrs(h.goas, groupOrAttrs{group: name})
+	return &h2
+}
+
+// restart the sequence at 1, e.g. in tests.
+// Applies to all handlers derived from h
+func (h *SequenceHandler) Reset() {
+	h.counter.Store(0)
+}
+
+// last sequence number given, 0 if none
+func (h *SequenceHandler) Last() uint64 {
+	return h.counter.Load()
+}
@@ -0,0 +1,87 @@
+package slogging
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/signal"
+	"sync"
+)
+
+// standard levels stepped through by signals
+var signalLevels = []slog.Level{slog.LevelDebug, slog.LevelInfo, slog.LevelWarn, slog.LevelError}
+
+// control the level of the handler h (as returned by Create) with signals:
+//
+//	SIGUSR1: step level down toward DEBUG
+//	SIGUSR2: step level up toward ERROR
+//	SIGHUP: reset to the initial level
+//
+// The same level is changed as through the http.Handler.
+// Call stop to stop listening for the signals; calling it again has no effect.
+// Not supported on platforms without these signals.
+func InstallSignalLevelControl(h http.Handler) (stop func(), err error) {
+	lh, ok := h.(logHandler)
+	if !ok {
+		return nil, errors.New("handler not created by this package")
+	}
+	if len(levelSignals) == 0 {
+		return nil, errors.New("level signals not supported on this platform")
+	}
+
+	ch := make(chan os.Signal, 1)
+	signal.Notify(ch, levelSignals...)
+	done := make(chan struct{})
+	go func() {
+		for {
+			select {
+			case <-done:
+				return
+			case sig := <-ch:
+				switch sig {
+				case levelSignals[signalDown]:
+					lh.step(-1)
+				case levelSignals[signalUp]:
+					lh.step(1)
+				case levelSignals[signalReset]:
+					lh.resetLevel(nil)
+				}
+			}
+		}
+	}()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			signal.Stop(ch)
+			close(done)
+		})
+	}, nil
+}
+
+// indices into levelSignals
+const (
+	signalDown = iota
+	signalUp
+	signalReset
+)
+
+// next standard level below (dir < 0) or above (dir > 0) lvl.
+// Stays at DEBUG or ERROR when there are no more levels
+func stepLevel(lvl slog.Level, dir int) slog.Level {
+	if dir < 0 {
+		for i := len(signalLevels) - 1; i >= 0; i-- {
+			if signalLevels[i] < lvl {
+				return signalLevels[i]
+			}
+		}
+		return min(lvl, signalLevels[0])
+	}
+	for _, x := range signalLevels {
+		if x > lvl {
+			return x
+		}
+	}
+	return max(lvl, signalLevels[len(signalLevels)-1])
+}
@@ -0,0 +1,8 @@
+//go:build !unix
+
+package slogging
+
+import "os"
+
+// no SIGUSR1/SIGUSR2 on this platform
+var levelSignals []os.Signal
@@ -0,0 +1,11 @@
+//go:build unix
+
+package slogging
+
+import (
+	"os"
+	"syscall"
+)
+
+// signals for stepping the level down, up and resetting it
+var levelSignals = []os.Signal{syscall.SIGUSR1, syscall.SIGUSR2, syscall.SIGHUP}
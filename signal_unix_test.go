@@ -0,0 +1,33 @@
+//go:build unix
+
+package slogging
+
+import (
+	"io"
+	"log/slog"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestInstallSignalLevelControl(t *testing.T) {
+	_, h := CreateWithOptions(slog.HandlerOptions{Level: slog.LevelInfo}, false, WithWriter(io.Discard))
+	stop, err := InstallSignalLevelControl(h)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := syscall.Kill(syscall.Getpid(), syscall.SIGUSR1); err != nil {
+		t.Fatal(err)
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for h.(logHandler).current.Level() != slog.LevelDebug {
+		if time.Now().After(deadline) {
+			t.Fatalf("expected level %s, got %s", slog.LevelDebug, h.(logHandler).current.Level())
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	stop()
+	stop()
+}
@@ -0,0 +1,58 @@
+package slogging
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"sync"
+)
+
+// lineSinkHandler formats each record with the next handler (writing to the
+// buffer of sink) and passes the formatted line, with the level, to the sink's
+// write func, e.g. for syslog with a severity per record
+type lineSinkHandler struct {
+	next slog.Handler
+	s    *lineSink
+}
+
+type lineSink struct {
+	write func(level slog.Level, line string) error
+
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+// create logger (with CreateWithOptions) formatting records into lines passed to write
+func createLineSink(opts slog.HandlerOptions, jsonOutput bool, write func(slog.Level, string) error, attrs []slog.Attr) (*slog.Logger, *lineSink, logHandler) {
+	s := &lineSink{write: write}
+	logger, h := CreateWithOptions(opts, jsonOutput,
+		WithWriter(&s.buf),
+		withWrapper(func(next slog.Handler) slog.Handler {
+			return &lineSinkHandler{next: next, s: s}
+		}),
+		WithAttrs(attrs...))
+	return logger, s, h.(logHandler)
+}
+
+func (h *lineSinkHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+func (h *lineSinkHandler) Handle(ctx context.Context, r slog.Record) error {
+	h.s.mu.Lock()
+	defer h.s.mu.Unlock()
+
+	h.s.buf.Reset()
+	if err := h.next.Handle(ctx, r); err != nil {
+		return err
+	}
+	return h.s.write(r.Level, string(bytes.TrimSuffix(h.s.buf.Bytes(), []byte("\n"))))
+}
+
+func (h *lineSinkHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &lineSinkHandler{next: h.next.WithAttrs(attrs), s: h.s}
+}
+
+func (h *lineSinkHandler) WithGroup(name string) slog.Handler {
+	return &lineSinkHandler{next: h.next.WithGroup(name), s: h.s}
+}
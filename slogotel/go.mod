@@ -0,0 +1,15 @@
+module github.com/bredtape/slogging/slogotel
+
+go 1.21.0
+
+require (
+	github.com/bredtape/slogging v0.0.0
+	go.opentelemetry.io/otel/trace v1.28.0
+)
+
+require (
+	go.opentelemetry.io/otel v1.28.0 // indirect
+	golang.org/x/sys v0.20.0 // indirect
+)
+
+replace github.com/bredtape/slogging => ../
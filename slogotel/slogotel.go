@@ -0,0 +1,43 @@
+// Package slogotel adds OpenTelemetry trace and span IDs to log records.
+// It is a separate module, so users of slogging not using OpenTelemetry
+// do not depend on it.
+package slogotel
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+
+	"github.com/bredtape/slogging"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// attribute keys
+const (
+	TraceIDKey = "trace_id"
+	SpanIDKey  = "span_id"
+)
+
+// trace_id and span_id attributes of the span in ctx.
+// Returns nil when there is no valid span
+func Attrs(ctx context.Context) []slog.Attr {
+	sc := trace.SpanContextFromContext(ctx)
+	if !sc.IsValid() {
+		return nil
+	}
+	return []slog.Attr{
+		slog.String(TraceIDKey, sc.TraceID().String()),
+		slog.String(SpanIDKey, sc.SpanID().String())}
+}
+
+// register Attrs with slogging.RegisterContextFunc, so loggers using
+// a slogging.ContextHandler (e.g. with the slogging.WithContextHandler option)
+// add trace_id and span_id to each record logged with a span in the context.
+// Calling it more than once has no effect
+func Register() {
+	registerOnce.Do(func() {
+		slogging.RegisterContextFunc(Attrs)
+	})
+}
+
+var registerOnce sync.Once
@@ -0,0 +1,43 @@
+// Package slogotlp exports log records to an OpenTelemetry collector with OTLP.
+// It is a separate module, so users of slogging not using OpenTelemetry
+// do not depend on it.
+package slogotlp
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+
+	"github.com/bredtape/slogging"
+	"go.opentelemetry.io/contrib/bridges/otelslog"
+	"go.opentelemetry.io/otel/exporters/otlp/otlplog/otlploghttp"
+	sdklog "go.opentelemetry.io/otel/sdk/log"
+)
+
+// name of the instrumentation scope of the records
+const scopeName = "github.com/bredtape/slogging/slogotlp"
+
+// create logger exporting records with OTLP over HTTP to endpoint,
+// e.g. "http://localhost:4318", in batches.
+// The level (opts.Level) may be changed with the returned http.Handler, as for
+// slogging.Create. opts.AddSource is honored, opts.ReplaceAttr is not used.
+// Call shutdown to flush remaining records and stop the exporter
+func CreateOTLP(ctx context.Context, endpoint string, opts slog.HandlerOptions, attrs ...slog.Attr) (
+	logger *slog.Logger, h http.Handler, shutdown func(context.Context) error, err error) {
+	exporter, err := otlploghttp.New(ctx, otlploghttp.WithEndpointURL(endpoint))
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	provider := sdklog.NewLoggerProvider(sdklog.WithProcessor(sdklog.NewBatchProcessor(exporter)))
+
+	handler := otelslog.NewHandler(scopeName,
+		otelslog.WithLoggerProvider(provider),
+		otelslog.WithSource(opts.AddSource))
+
+	level := opts.Level
+	if level == nil {
+		level = slog.LevelInfo
+	}
+	logger, h = slogging.CreateWithHandler(handler, level, attrs...)
+	return logger, h, provider.Shutdown, nil
+}
@@ -0,0 +1,15 @@
+module github.com/bredtape/slogging/slogzap
+
+go 1.21.0
+
+require (
+	github.com/bredtape/slogging v0.0.0
+	go.uber.org/zap v1.27.0
+)
+
+require (
+	go.uber.org/multierr v1.10.0 // indirect
+	golang.org/x/sys v0.20.0 // indirect
+)
+
+replace github.com/bredtape/slogging => ../
@@ -0,0 +1,166 @@
+// Package slogzap provides a slog.Handler forwarding records to a zapcore.Core,
+// so services can migrate to slog while keeping their existing zap outputs.
+// It is a separate module, so users of slogging not using zap
+// do not depend on it.
+package slogzap
+
+import (
+	"context"
+	"log/slog"
+	"runtime"
+
+	"github.com/bredtape/slogging"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// Handler is a slog.Handler writing records to a zapcore.Core.
+// Attributes are converted to zap fields and groups to zap namespaces.
+// Levels are mapped to the zap level at or below, see Level
+type Handler struct {
+	core zapcore.Core
+	// open groups, as namespaces, whose fields are not yet added to core.
+	// Groups without attributes are omitted, as with the slog handlers
+	groups []string
+}
+
+// create Handler writing to core
+func NewHandler(core zapcore.Core) *Handler {
+	return &Handler{core: core}
+}
+
+// zap level for a slog level: FATAL (slogging.LevelFatal) and above to
+// FatalLevel, ERROR to ErrorLevel, WARN to WarnLevel, INFO to InfoLevel and
+// DEBUG (and below, e.g. TRACE) to DebugLevel.
+// zapcore.Core does not exit for FatalLevel, only zap.Logger does
+func Level(level slog.Level) zapcore.Level {
+	switch {
+	case level >= slogging.LevelFatal:
+		return zapcore.FatalLevel
+	case level >= slog.LevelError:
+		return zapcore.ErrorLevel
+	case level >= slog.LevelWarn:
+		return zapcore.WarnLevel
+	case level >= slog.LevelInfo:
+		return zapcore.InfoLevel
+	default:
+		return zapcore.DebugLevel
+	}
+}
+
+func (h *Handler) Enabled(_ context.Context, level slog.Level) bool {
+	return h.core.Enabled(Level(level))
+}
+
+func (h *Handler) Handle(_ context.Context, r slog.Record) error {
+	entry := zapcore.Entry{
+		Level:   Level(r.Level),
+		Time:    r.Time,
+		Message: r.Message}
+	if r.PC != 0 {
+		fs := runtime.CallersFrames([]uintptr{r.PC})
+		f, _ := fs.Next()
+		entry.Caller = zapcore.NewEntryCaller(f.PC, f.File, f.Line, true)
+		entry.Caller.Function = f.Function
+	}
+	ce := h.core.Check(entry, nil)
+	if ce == nil {
+		return nil
+	}
+
+	fields := make([]zapcore.Field, 0, r.NumAttrs())
+	r.Attrs(func(a slog.Attr) bool {
+		fields = appendField(fields, a)
+		return true
+	})
+	if len(fields) > 0 {
+		fields = nest(h.groups, fields)
+	}
+	ce.Write(fields...)
+	return nil
+}
+
+func (h *Handler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	var fields []zapcore.Field
+	for _, a := range attrs {
+		fields = appendField(fields, a)
+	}
+	if len(fields) == 0 {
+		return h
+	}
+	return &Handler{core: h.core.With(nest(h.groups, fields))}
+}
+
+func (h *Handler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	return &Handler{core: h.core, groups: append(h.groups[:len(h.groups):len(h.groups)], name)}
+}
+
+// prefix fields with namespaces for groups
+func nest(groups []string, fields []zapcore.Field) []zapcore.Field {
+	if len(groups) == 0 {
+		return fields
+	}
+	xs := make([]zapcore.Field, 0, len(groups)+len(fields))
+	for _, g := range groups {
+		xs = append(xs, zap.Namespace(g))
+	}
+	return append(xs, fields...)
+}
+
+// append a as a field. Groups are added as objects, or inline for an empty key
+func appendField(fields []zapcore.Field, a slog.Attr) []zapcore.Field {
+	v := a.Value.Resolve()
+	switch v.Kind() {
+	case slog.KindGroup:
+		attrs := v.Group()
+		if len(attrs) == 0 {
+			return fields
+		}
+		if a.Key == "" {
+			for _, x := range attrs {
+				fields = appendField(fields, x)
+			}
+			return fields
+		}
+		return append(fields, zap.Object(a.Key, groupMarshaler(attrs)))
+	case slog.KindString:
+		return append(fields, zap.String(a.Key, v.String()))
+	case slog.KindInt64:
+		return append(fields, zap.Int64(a.Key, v.Int64()))
+	case slog.KindUint64:
+		return append(fields, zap.Uint64(a.Key, v.Uint64()))
+	case slog.KindFloat64:
+		return append(fields, zap.Float64(a.Key, v.Float64()))
+	case slog.KindBool:
+		return append(fields, zap.Bool(a.Key, v.Bool()))
+	case slog.KindDuration:
+		return append(fields, zap.Duration(a.Key, v.Duration()))
+	case slog.KindTime:
+		return append(fields, zap.Time(a.Key, v.Time()))
+	default:
+		if a.Key == "" {
+			return fields
+		}
+		if err, ok := v.Any().(error); ok {
+			return append(fields, zap.NamedError(a.Key, err))
+		}
+		return append(fields, zap.Any(a.Key, v.Any()))
+	}
+}
+
+// attributes of a group as a zap object
+type groupMarshaler []slog.Attr
+
+func (g groupMarshaler) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	var fields []zapcore.Field
+	for _, a := range g {
+		fields = appendField(fields, a)
+	}
+	for _, f := range fields {
+		f.AddTo(enc)
+	}
+	return nil
+}
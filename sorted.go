@@ -0,0 +1,57 @@
+package slogging
+
+import (
+	"cmp"
+	"context"
+	"log/slog"
+	"slices"
+)
+
+// sorts the attributes of each record by key within each group, including
+// those from WithAttrs, see WithSortedKeys
+type sortedKeysHandler struct {
+	next slog.Handler
+	// all groups and attributes, as they must be sorted with those of the record
+	goas []groupOrAttrs
+}
+
+func (h *sortedKeysHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+func (h *sortedKeysHandler) Handle(ctx context.Context, r slog.Record) error {
+	nr := slog.NewRecord(r.Time, r.Level, r.Message, r.PC)
+	nr.AddAttrs(sortAttrs(nestAttrs(h.goas, recordAttrs(r)))...)
+	return h.next.Handle(ctx, nr)
+}
+
+func (h *sortedKeysHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	if len(attrs) == 0 {
+		return h
+	}
+	return &sortedKeysHandler{next: h.next, goas: appendGroupOrAttrs(h.goas, groupOrAttrs{attrs: attrs})}
+}
+
+func (h *sortedKeysHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	return &sortedKeysHandler{next: h.next, goas: appendGroupOrAttrs(h.goas, groupOrAttrs{group: name})}
+}
+
+// sorted copy of attrs by key, recursively for groups. Inline groups (empty key)
+// are merged into the enclosing group first. The sort is stable, so duplicate
+// keys keep their order
+func sortAttrs(attrs []slog.Attr) []slog.Attr {
+	// resolved, with inline groups (at any depth) merged
+	xs := flattenInline(make([]slog.Attr, 0, len(attrs)), attrs)
+	for i, a := range xs {
+		if a.Value.Kind() == slog.KindGroup {
+			xs[i].Value = slog.GroupValue(sortAttrs(a.Value.Group())...)
+		}
+	}
+	slices.SortStableFunc(xs, func(a, b slog.Attr) int {
+		return cmp.Compare(a.Key, b.Key)
+	})
+	return xs
+}
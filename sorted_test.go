@@ -0,0 +1,24 @@
+package slogging
+
+import (
+	"bytes"
+	"log/slog"
+	"testing"
+)
+
+func TestSortedKeys(t *testing.T) {
+	var buf bytes.Buffer
+	log, _ := CreateWithOptions(slog.HandlerOptions{Level: slog.LevelInfo}, true,
+		WithWriter(&buf), WithoutTime(), WithSortedKeys(true))
+
+	log.With("y", 1).WithGroup("w").With("d", 1).Info("m",
+		"c", 1,
+		slog.Group("", slog.Group("g", "z", 1, "a", 2)),
+		slog.Group("", "b", 1),
+		slog.Group("h", "z", 1, slog.Group("i", "y", 1, "x", 2), slog.Group("", "a", 1)))
+
+	expected := `{"level":"INFO","msg":"m","w":{"b":1,"c":1,"d":1,"g":{"a":2,"z":1},"h":{"a":1,"i":{"x":2,"y":1},"z":1}},"y":1}` + "\n"
+	if buf.String() != expected {
+		t.Errorf("expected:\n%s\ngot:\n%s", expected, buf.String())
+	}
+}
@@ -0,0 +1,150 @@
+package slogging
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// how SourceTrim rewrites the source file
+type SourceTrimMode int
+
+const (
+	// the base name of the file, e.g. "log.go"
+	SourceTrimBase SourceTrimMode = iota
+	// the file relative to the main module, e.g. "internal/db/db.go".
+	// Files outside the main module are prefixed with their package path,
+	// e.g. "github.com/some/dep/pkg/file.go"
+	SourceTrimModule
+)
+
+// returns a ReplaceAttr func for slog.HandlerOptions, which shortens the file
+// of the source attribute (with AddSource) as specified by mode, so logs do not
+// contain paths of the build machine
+func SourceTrim(mode SourceTrimMode) func(groups []string, a slog.Attr) slog.Attr {
+	modulePath := ""
+	if info, ok := readBuildInfo(); ok {
+		modulePath = info.Main.Path
+	}
+
+	return func(groups []string, a slog.Attr) slog.Attr {
+		if len(groups) > 0 || a.Key != slog.SourceKey {
+			return a
+		}
+		src, ok := a.Value.Any().(*slog.Source)
+		if !ok || src == nil {
+			return a
+		}
+
+		trimmed := *src
+		trimmed.File = filepath.Base(src.File)
+		if mode == SourceTrimModule {
+			if pkg := packagePath(src.Function); pkg != "" {
+				dir := pkg
+				// functions of main packages are named main.X, regardless of directory
+				if pkg == modulePath || pkg == "main" {
+					dir = ""
+				} else if modulePath != "" && strings.HasPrefix(pkg, modulePath+"/") {
+					dir = strings.TrimPrefix(pkg, modulePath+"/")
+				}
+				trimmed.File = strings.TrimPrefix(dir+"/"+trimmed.File, "/")
+			}
+		}
+		a.Value = slog.AnyValue(&trimmed)
+		return a
+	}
+}
+
+// package path of a fully-qualified function name,
+// e.g. "github.com/a/b/pkg" for "github.com/a/b/pkg.(*T).Method"
+func packagePath(function string) string {
+	lastSlash := strings.LastIndex(function, "/")
+	dot := strings.Index(function[lastSlash+1:], ".")
+	if dot < 0 {
+		return ""
+	}
+	return function[:lastSlash+1+dot]
+}
+
+// adds the source of the record PC as the attribute slog.SourceKey, first at the
+// top level, for records at or above minLevel. Used in place of AddSource,
+// which places the source before the message
+type sourceHandler struct {
+	next     slog.Handler
+	minLevel slog.Level
+	goas     []groupOrAttrs
+}
+
+func (h *sourceHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+func (h *sourceHandler) Handle(ctx context.Context, r slog.Record) error {
+	if r.Level < h.minLevel || r.PC == 0 {
+		if len(h.goas) == 0 {
+			return h.next.Handle(ctx, r)
+		}
+		nr := slog.NewRecord(r.Time, r.Level, r.Message, r.PC)
+		nr.AddAttrs(nestAttrs(h.goas, recordAttrs(r))...)
+		return h.next.Handle(ctx, nr)
+	}
+
+	fs := runtime.CallersFrames([]uintptr{r.PC})
+	f, _ := fs.Next()
+	src := slog.Any(slog.SourceKey, &slog.Source{Function: f.Function, File: f.File, Line: f.Line})
+	nr := slog.NewRecord(r.Time, r.Level, r.Message, r.PC)
+	nr.AddAttrs(src)
+	nr.AddAttrs(nestAttrs(h.goas, recordAttrs(r))...)
+	return h.next.Handle(ctx, nr)
+}
+
+func (h *sourceHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	if len(attrs) == 0 {
+		return h
+	}
+	return &sourceHandler{next: h.next, minLevel: h.minLevel, goas: appendGroupOrAttrs(h.goas, groupOrAttrs{attrs: attrs})}
+}
+
+func (h *sourceHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	return &sourceHandler{next: h.next, minLevel: h.minLevel, goas: appendGroupOrAttrs(h.goas, groupOrAttrs{group: name})}
+}
+
+// ReplaceAttr func rendering the source attribute (of AddSource or
+// sourceHandler) as the handlers render their own: an object in JSON, and
+// file:line in text. Applied last, so the JSON handler does not pass the fields
+// of the object to ReplaceAttr again, as if they were top-level attributes.
+// An empty source is dropped, as by the handlers
+func renderSource(groups []string, a slog.Attr) slog.Attr {
+	if len(groups) > 0 || a.Key != slog.SourceKey {
+		return a
+	}
+	if src, ok := a.Value.Any().(*slog.Source); ok {
+		if src == nil || *src == (slog.Source{}) {
+			return slog.Attr{}
+		}
+		a.Value = slog.AnyValue(sourceValue(*src))
+	}
+	return a
+}
+
+type sourceValue slog.Source
+
+// the non-zero fields, as the JSON handler writes a *slog.Source
+func (s sourceValue) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Function string `json:"function,omitempty"`
+		File     string `json:"file,omitempty"`
+		Line     int    `json:"line,omitempty"`
+	}(s))
+}
+
+func (s sourceValue) String() string {
+	return fmt.Sprintf("%s:%d", s.File, s.Line)
+}
@@ -0,0 +1,182 @@
+package slogging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"slices"
+	"testing"
+)
+
+// top-level keys of the JSON object in line, in the order written
+func topLevelKeys(t *testing.T, line []byte) []string {
+	t.Helper()
+	dec := json.NewDecoder(bytes.NewReader(line))
+	if tok, err := dec.Token(); err != nil || tok != json.Delim('{') {
+		t.Fatalf("expected JSON object, got %q", line)
+	}
+	var keys []string
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			t.Fatalf("invalid JSON %q: %v", line, err)
+		}
+		keys = append(keys, tok.(string))
+		var v json.RawMessage
+		if err := dec.Decode(&v); err != nil {
+			t.Fatalf("invalid JSON %q: %v", line, err)
+		}
+	}
+	return keys
+}
+
+func TestFixedFieldOrderJSON(t *testing.T) {
+	rename := func(groups []string, a slog.Attr) slog.Attr {
+		if len(groups) == 0 {
+			switch a.Key {
+			case slog.TimeKey:
+				a.Key = "ts"
+			case slog.LevelKey:
+				a.Key = "lvl"
+			case slog.MessageKey:
+				a.Key = "text"
+			}
+		}
+		return a
+	}
+
+	for _, tc := range []struct {
+		name     string
+		opts     slog.HandlerOptions
+		options  []Option
+		expected []string
+	}{
+		{"default names", slog.HandlerOptions{AddSource: true}, nil,
+			[]string{"time", "level", "msg", "source", "a", "g"}},
+		{"no source", slog.HandlerOptions{}, nil,
+			[]string{"time", "level", "msg", "a", "g"}},
+		{"renamed by ReplaceAttr", slog.HandlerOptions{AddSource: true, ReplaceAttr: rename}, nil,
+			[]string{"ts", "lvl", "text", "source", "a", "g"}},
+		{"GCP names", slog.HandlerOptions{AddSource: true}, []Option{WithGCPFieldNames()},
+			[]string{"timestamp", "severity", "message", "source", "a", "g"}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			tc.opts.Level = slog.LevelInfo
+			options := append([]Option{WithWriter(&buf), WithFixedFieldOrder()}, tc.options...)
+			log, _ := CreateWithOptions(tc.opts, true, options...)
+
+			log.With("a", 1).WithGroup("g").With("b", 2).Info("m", "c", 3)
+
+			keys := topLevelKeys(t, buf.Bytes())
+			if !slices.Equal(keys, tc.expected) {
+				t.Errorf("expected keys %v, got %v in %s", tc.expected, keys, buf.Bytes())
+			}
+			prefix := `{"` + tc.expected[0] + `":`
+			if !bytes.HasPrefix(buf.Bytes(), []byte(prefix)) {
+				t.Errorf("expected line to start with %s, got %s", prefix, buf.Bytes())
+			}
+			if !bytes.Contains(buf.Bytes(), []byte(`"g":{"b":2,"c":3}}`)) {
+				t.Errorf("expected group last, got %s", buf.Bytes())
+			}
+		})
+	}
+}
+
+func TestFixedFieldOrderText(t *testing.T) {
+	var buf bytes.Buffer
+	log, _ := CreateWithOptions(slog.HandlerOptions{Level: slog.LevelInfo, AddSource: true}, false,
+		WithWriter(&buf), WithFixedFieldOrder())
+
+	log.With("a", 1).WithGroup("g").Info("m", "c", 3)
+
+	expected := regexp.MustCompile(`^time=\S+ level=INFO msg=m source=\S+/source_test.go:\d+ a=1 g.c=3\n$`)
+	if !expected.Match(buf.Bytes()) {
+		t.Errorf("expected %s, got %q", expected, buf.Bytes())
+	}
+}
+
+func TestSourceAboveLevelIsCaller(t *testing.T) {
+	var buf bytes.Buffer
+	log, _ := CreateWithOptions(slog.HandlerOptions{Level: slog.LevelDebug}, true,
+		WithWriter(&buf), WithSourceAboveLevel(slog.LevelWarn), WithCallerFunc(false))
+	defer func(f func(int)) { exitFunc = f }(exitFunc)
+	exitFunc = func(int) {}
+
+	for _, tc := range []struct {
+		name       string
+		log        func() int // logs and returns the line logged from
+		withSource bool
+	}{
+		{"Info", func() int {
+			line := callerLine() + 1
+			log.Info("m")
+			return line
+		}, false},
+		{"Infof", func() int {
+			line := callerLine() + 1
+			Infof(log, "m %d", 1)
+			return line
+		}, false},
+		{"Warn", func() int {
+			line := callerLine() + 1
+			log.Warn("m")
+			return line
+		}, true},
+		{"Errorf", func() int {
+			line := callerLine() + 1
+			Errorf(log, "m %d", 1)
+			return line
+		}, true},
+		{"LogAt", func() int {
+			line := callerLine() + 1
+			LogAt(context.Background(), log, slog.LevelError, "m")
+			return line
+		}, true},
+		{"Fatal", func() int {
+			line := callerLine() + 1
+			Fatal(log, "m")
+			return line
+		}, true},
+		{"Panic", func() (line int) {
+			defer func() { _ = recover() }()
+			line = callerLine() + 1
+			Panic(log, "m")
+			return line
+		}, true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			buf.Reset()
+			line := tc.log()
+
+			var rec struct {
+				Source *slog.Source `json:"source"`
+			}
+			if err := json.Unmarshal(buf.Bytes(), &rec); err != nil {
+				t.Fatalf("invalid JSON %q: %v", buf.Bytes(), err)
+			}
+			if !tc.withSource {
+				if rec.Source != nil {
+					t.Errorf("expected no source below the threshold, got %+v", rec.Source)
+				}
+				return
+			}
+			if rec.Source == nil {
+				t.Fatalf("expected source, got %s", buf.Bytes())
+			}
+			if filepath.Base(rec.Source.File) != "source_test.go" || rec.Source.Line != line {
+				t.Errorf("expected source source_test.go:%d, got %s:%d", line, rec.Source.File, rec.Source.Line)
+			}
+		})
+	}
+}
+
+// line of the caller
+func callerLine() int {
+	_, _, line, _ := runtime.Caller(1)
+	return line
+}
@@ -0,0 +1,84 @@
+package slogging
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"log"
+	"log/slog"
+	"sync"
+)
+
+// route output of the standard library's global logger (log.Printf etc.)
+// through logger at level. Note that this changes the global std logger
+// for the whole process, including its flags and prefix.
+// slog.SetDefault (and SetDefaults) already does this at INFO level.
+// logger must not be the initial slog.Default(), which writes to the std logger
+func BridgeStdLog(logger *slog.Logger, level slog.Level) {
+	ll := slog.NewLogLogger(logger.Handler(), level)
+	log.SetFlags(0)
+	log.SetPrefix("")
+	log.SetOutput(ll.Writer())
+}
+
+// max length of a line buffered by WriterAt, before it is logged without a newline
+const maxWriterLine = 64 << 10
+
+// returns an io.WriteCloser logging each line written to it as a record at
+// level, e.g. for a library logging to an io.Writer.
+// Partial lines are buffered until the newline. Empty lines are not logged,
+// and a trailing "\r" is removed. Close logs a final unterminated line.
+// It is safe for concurrent use
+func WriterAt(log *slog.Logger, level slog.Level) io.WriteCloser {
+	return &levelWriter{log: log, level: level}
+}
+
+type levelWriter struct {
+	log   *slog.Logger
+	level slog.Level
+
+	mu  sync.Mutex
+	buf []byte
+}
+
+func (w *levelWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.buf = append(w.buf, p...)
+	for {
+		i := bytes.IndexByte(w.buf, '\n')
+		if i < 0 {
+			break
+		}
+		w.logLine(w.buf[:i])
+		w.buf = w.buf[i+1:]
+	}
+	if len(w.buf) > maxWriterLine {
+		w.logLine(w.buf)
+		w.buf = nil
+	}
+	if len(w.buf) == 0 {
+		// release the backing array
+		w.buf = nil
+	}
+	return len(p), nil
+}
+
+// log the final unterminated line, if any
+func (w *levelWriter) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.logLine(w.buf)
+	w.buf = nil
+	return nil
+}
+
+func (w *levelWriter) logLine(line []byte) {
+	line = bytes.TrimSuffix(line, []byte("\r"))
+	if len(bytes.TrimSpace(line)) == 0 {
+		return
+	}
+	w.log.Log(context.Background(), w.level, string(line))
+}
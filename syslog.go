@@ -0,0 +1,39 @@
+//go:build !windows && !plan9
+
+package slogging
+
+import (
+	"io"
+	"log/slog"
+	"log/syslog"
+	"net/http"
+)
+
+// create logger (like Create) sending records to a syslog server, see syslog.Dial.
+// Use network and addr "" to connect to the local syslog server.
+// Levels are mapped to syslog severities: FATAL to LOG_CRIT, ERROR to LOG_ERR,
+// WARN to LOG_WARNING, INFO to LOG_INFO and DEBUG (and below) to LOG_DEBUG.
+// The connection is re-established if it drops.
+// Close the returned io.Closer on shutdown
+func CreateSyslog(network, addr, tag string, opts slog.HandlerOptions, jsonOutput bool, attrs ...slog.Attr) (*slog.Logger, http.Handler, io.Closer, error) {
+	w, err := syslog.Dial(network, addr, syslog.LOG_INFO|syslog.LOG_USER, tag)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	logger, _, h := createLineSink(opts, jsonOutput, func(level slog.Level, line string) error {
+		switch {
+		case level >= LevelFatal:
+			return w.Crit(line)
+		case level >= slog.LevelError:
+			return w.Err(line)
+		case level >= slog.LevelWarn:
+			return w.Warning(line)
+		case level >= slog.LevelInfo:
+			return w.Info(line)
+		default:
+			return w.Debug(line)
+		}
+	}, attrs)
+	return logger, h, w, nil
+}
@@ -0,0 +1,30 @@
+package slogging
+
+import (
+	"log/slog"
+	"time"
+)
+
+// layout for TimeFormat to drop the time attribute entirely,
+// e.g. when journald adds its own timestamp
+const NoTime = ""
+
+// returns a ReplaceAttr func for slog.HandlerOptions, which formats the time
+// of each record with layout, e.g. time.RFC3339Nano.
+// The time is converted to loc, unless loc is nil (keeping local time).
+// Use layout NoTime to drop the time attribute.
+func TimeFormat(layout string, loc *time.Location) func(groups []string, a slog.Attr) slog.Attr {
+	return func(groups []string, a slog.Attr) slog.Attr {
+		if len(groups) > 0 || a.Key != slog.TimeKey || a.Value.Kind() != slog.KindTime {
+			return a
+		}
+		if layout == NoTime {
+			return slog.Attr{}
+		}
+		t := a.Value.Time()
+		if loc != nil {
+			t = t.In(loc)
+		}
+		return slog.String(a.Key, t.Format(layout))
+	}
+}
@@ -0,0 +1,82 @@
+package slogging
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTimeFormat(t *testing.T) {
+	cet := time.FixedZone("CET", 3600)
+	ts := time.Date(2024, 5, 1, 12, 30, 0, 123456789, cet)
+
+	for _, tc := range []struct {
+		name     string
+		layout   string
+		loc      *time.Location
+		expected string
+	}{
+		{"keep location", time.RFC3339Nano, nil, "2024-05-01T12:30:00.123456789+01:00"},
+		{"UTC", time.RFC3339Nano, time.UTC, "2024-05-01T11:30:00.123456789Z"},
+		{"other location", time.DateTime, time.FixedZone("EST", -5*3600), "2024-05-01 06:30:00"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			a := TimeFormat(tc.layout, tc.loc)(nil, slog.Time(slog.TimeKey, ts))
+			if a.Key != slog.TimeKey || a.Value.String() != tc.expected {
+				t.Errorf("expected %s=%s, got %s", slog.TimeKey, tc.expected, a)
+			}
+		})
+	}
+
+	t.Run("time attribute in group", func(t *testing.T) {
+		a := slog.Time(slog.TimeKey, ts)
+		if got := TimeFormat(time.Kitchen, time.UTC)([]string{"g"}, a); !got.Equal(a) {
+			t.Errorf("expected unchanged, got %s", got)
+		}
+	})
+	t.Run("NoTime", func(t *testing.T) {
+		if a := TimeFormat(NoTime, nil)(nil, slog.Time(slog.TimeKey, ts)); !a.Equal(slog.Attr{}) {
+			t.Errorf("expected time dropped, got %s", a)
+		}
+	})
+}
+
+func TestWithTimeFormatComposesWithReplaceAttr(t *testing.T) {
+	// the user ReplaceAttr sees the original time value
+	var kind slog.Kind
+	user := func(groups []string, a slog.Attr) slog.Attr {
+		if a.Key == slog.TimeKey {
+			kind = a.Value.Kind()
+		}
+		if a.Key == "secret" {
+			return slog.String(a.Key, "***")
+		}
+		return a
+	}
+
+	var buf bytes.Buffer
+	log, _ := CreateWithOptions(slog.HandlerOptions{Level: slog.LevelInfo, ReplaceAttr: user}, false,
+		WithWriter(&buf), WithTimeFormat(time.RFC3339Nano, time.UTC))
+	log.Info("m", "secret", "x")
+
+	if kind != slog.KindTime {
+		t.Errorf("expected the user ReplaceAttr to see a time, got %s", kind)
+	}
+	line := buf.String()
+	ts, rest, _ := strings.Cut(strings.TrimPrefix(line, "time="), " ")
+	if _, err := time.Parse(time.RFC3339Nano, ts); err != nil || !strings.HasSuffix(ts, "Z") {
+		t.Errorf("expected UTC RFC3339Nano time, got %q", line)
+	}
+	if rest != "level=INFO msg=m secret=***\n" {
+		t.Errorf("unexpected line %q", line)
+	}
+
+	buf.Reset()
+	log, _ = CreateWithOptions(slog.HandlerOptions{Level: slog.LevelInfo}, true, WithWriter(&buf), WithoutTime())
+	log.Info("m")
+	if expected := `{"level":"INFO","msg":"m"}` + "\n"; buf.String() != expected {
+		t.Errorf("expected %q, got %q", expected, buf.String())
+	}
+}
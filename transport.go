@@ -0,0 +1,59 @@
+package slogging
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+// returns an http.RoundTripper logging each outbound request with method,
+// host, path, status, duration and response size (the Content-Length, when known)
+// to log, with the request context. Logs at DEBUG, or WARN for 4xx and ERROR for
+// 5xx status codes. Failed requests are logged at ERROR, or WARN if canceled.
+// Bodies, query and headers (e.g. Authorization) are not logged.
+// Errors are returned unchanged.
+// If base is nil, http.DefaultTransport is used
+func LoggingTransport(base http.RoundTripper, log *slog.Logger) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &loggingTransport{base: base, log: log}
+}
+
+type loggingTransport struct {
+	base http.RoundTripper
+	log  *slog.Logger
+}
+
+func (t *loggingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	start := time.Now()
+	resp, err := t.base.RoundTrip(req)
+
+	attrs := []slog.Attr{
+		slog.String("method", req.Method),
+		slog.String("host", req.URL.Host),
+		slog.String("path", req.URL.Path),
+		slog.Duration("duration", time.Since(start))}
+	if err != nil {
+		attrs = append(attrs, slog.String("error", err.Error()))
+		level := slog.LevelError
+		if errors.Is(err, context.Canceled) {
+			level = slog.LevelWarn
+		}
+		t.log.LogAttrs(req.Context(), level, "outbound request failed", attrs...)
+		return resp, err
+	}
+
+	attrs = append(attrs, slog.Int("status", resp.StatusCode))
+	if resp.ContentLength >= 0 {
+		attrs = append(attrs, slog.Int64("size", resp.ContentLength))
+	}
+	level := slog.LevelDebug
+	if resp.StatusCode >= 400 {
+		level = statusLevel(resp.StatusCode)
+	}
+	t.log.LogAttrs(req.Context(), level, "outbound request", attrs...)
+	return resp, nil
+}
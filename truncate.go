@@ -0,0 +1,52 @@
+package slogging
+
+import (
+	"fmt"
+	"log/slog"
+	"unicode/utf8"
+)
+
+// default limit for MaxAttrLen
+const DefaultMaxAttrLen = 8 * 1024
+
+// returns a ReplaceAttr func for slog.HandlerOptions, which truncates string
+// values longer than n bytes, appending "…(truncated, N bytes)" with the
+// original length. Other values (except numbers, bools, times and durations)
+// are truncated as their string form, when it is too long. The built-in time,
+// level and source are not truncated, but the message is. With AddSource,
+// prefer WithMaxAttrLen, as the slog JSON handler passes the fields of the
+// source to ReplaceAttr too, e.g. "file" as at the top level.
+// Values of a slog.LogValuer are resolved before ReplaceAttr is called,
+// and it is called for each attribute in groups.
+// n <= 0 means DefaultMaxAttrLen
+func MaxAttrLen(n int) func(groups []string, a slog.Attr) slog.Attr {
+	if n <= 0 {
+		n = DefaultMaxAttrLen
+	}
+	return func(groups []string, a slog.Attr) slog.Attr {
+		if isBuiltin(groups, a) {
+			return a
+		}
+		switch a.Value.Kind() {
+		case slog.KindString, slog.KindAny:
+			if s := a.Value.String(); len(s) > n {
+				a.Value = slog.StringValue(truncateString(s, n))
+			}
+		}
+		return a
+	}
+}
+
+// whether a is the built-in time, level or source
+func isBuiltin(groups []string, a slog.Attr) bool {
+	return len(groups) == 0 && (a.Key == slog.TimeKey || a.Key == slog.LevelKey || a.Key == slog.SourceKey)
+}
+
+// s truncated to at most n bytes (at a rune boundary) with a marker
+func truncateString(s string, n int) string {
+	cut := n
+	for cut > 0 && !utf8.RuneStart(s[cut]) {
+		cut--
+	}
+	return s[:cut] + fmt.Sprintf("…(truncated, %d bytes)", len(s))
+}
@@ -0,0 +1,41 @@
+package slogging
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestMaxAttrLen(t *testing.T) {
+	var buf bytes.Buffer
+	log, _ := CreateWithOptions(slog.HandlerOptions{Level: slog.LevelInfo, AddSource: true}, true,
+		WithWriter(&buf), WithMaxAttrLen(10))
+
+	log.Info("a long message", "s", "0123456789abc", "short", "012", slog.Group("g", "source", strings.Repeat("x", 11)))
+
+	var rec struct {
+		Source *slog.Source
+		Msg    string
+		S      string
+		Short  string
+		G      struct{ Source string }
+	}
+	if err := json.Unmarshal(buf.Bytes(), &rec); err != nil {
+		t.Fatalf("invalid JSON %q: %v", buf.Bytes(), err)
+	}
+	if rec.Source == nil || !strings.HasSuffix(rec.Source.File, "truncate_test.go") {
+		t.Errorf("expected the source not truncated, got %s", buf.Bytes())
+	}
+	for name, x := range map[string][2]string{
+		"msg":      {rec.Msg, "a long mes…(truncated, 14 bytes)"},
+		"s":        {rec.S, "0123456789…(truncated, 13 bytes)"},
+		"short":    {rec.Short, "012"},
+		"g.source": {rec.G.Source, "xxxxxxxxxx…(truncated, 11 bytes)"},
+	} {
+		if x[0] != x[1] {
+			t.Errorf("expected %s=%q, got %q", name, x[1], x[0])
+		}
+	}
+}
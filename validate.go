@@ -0,0 +1,47 @@
+package slogging
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"sync/atomic"
+	"time"
+)
+
+// number of invalid JSON records replaced, see WithValidateJSON
+var invalidJSONRecords atomic.Uint64
+
+// number of JSON records found invalid (and replaced) by loggers with
+// WithValidateJSON, since the process started
+func InvalidJSONRecords() uint64 {
+	return invalidJSONRecords.Load()
+}
+
+// checks that each written record is valid JSON. An invalid record is replaced
+// by an ERROR record holding the invalid record as a string, so that the output
+// stays valid JSON lines, and counted in invalidJSONRecords
+type validatingJSONWriter struct {
+	w io.Writer
+}
+
+func (v *validatingJSONWriter) Write(b []byte) (int, error) {
+	if json.Valid(b) {
+		return v.w.Write(b)
+	}
+	invalidJSONRecords.Add(1)
+
+	fallback, _ := json.Marshal(struct {
+		Time    time.Time `json:"time"`
+		Level   string    `json:"level"`
+		Msg     string    `json:"msg"`
+		Invalid string    `json:"invalidRecord"`
+	}{
+		Time:    time.Now(),
+		Level:   "ERROR",
+		Msg:     "invalid JSON log record",
+		Invalid: string(bytes.TrimSuffix(b, []byte("\n")))})
+	if _, err := v.w.Write(append(fallback, '\n')); err != nil {
+		return 0, err
+	}
+	return len(b), nil
+}